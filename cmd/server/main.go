@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"bem_be/internal/auth"
 	"bem_be/internal/auth/campus"
@@ -26,6 +27,12 @@ func main() {
 		log.Println("Warning: .env file not found, using environment variables")
 	}
 
+	// Check-in QR tokens must not be signed with the login secret
+	checkInSecret := os.Getenv("CHECKIN_TOKEN_SECRET")
+	if checkInSecret == "" || checkInSecret == os.Getenv("JWT_SECRET") {
+		log.Fatal("CHECKIN_TOKEN_SECRET must be set and differ from JWT_SECRET")
+	}
+
 	// Set Gin mode
 	gin.SetMode(utils.GetEnvWithDefault("GIN_MODE", "debug"))
 
@@ -42,6 +49,18 @@ func main() {
 		log.Fatalf("Error creating admin user: %v", err)
 	}
 
+	// Generate slugs for news and announcements created before slugs existed
+	if err := services.NewNewsService(database.DB).BackfillSlugs(); err != nil {
+		log.Printf("Warning: failed to generate news slugs: %v", err)
+	}
+	if err := services.NewAnnouncementService(database.DB).BackfillSlugs(); err != nil {
+		log.Printf("Warning: failed to generate announcement slugs: %v", err)
+	}
+
+	// Publish scheduled news in the background
+	newsPublishInterval := time.Duration(utils.GetEnvAsInt("NEWS_PUBLISH_INTERVAL_SECONDS", 60)) * time.Second
+	services.NewNewsService(database.DB).StartScheduledPublisher(newsPublishInterval)
+
 	// Create a new Gin router
 	router := gin.Default()
 
@@ -79,12 +98,42 @@ func main() {
 	departmentHandler := handlers.NewDepartmentHandler(database.DB)
 	organizationHandler := handlers.NewOrganizationHandler(database.DB)
 	requestHandler := handlers.NewRequestHandler(database.DB)
+	activityHandler := handlers.NewActivityHandler(database.DB)
+	proposalHandler := handlers.NewProposalHandler(database.DB)
+	reportHandler := handlers.NewReportHandler(database.DB)
+	financeHandler := handlers.NewFinanceHandler(database.DB)
+	budgetHandler := handlers.NewBudgetHandler(database.DB)
+	calendarHandler := handlers.NewCalendarHandler(database.DB)
+	registrationHandler := handlers.NewRegistrationHandler(database.DB)
+	attendanceHandler := handlers.NewAttendanceHandler(database.DB)
+	venueHandler := handlers.NewVenueHandler(database.DB)
+	inventoryHandler := handlers.NewInventoryHandler(database.DB)
+	letterHandler := handlers.NewLetterHandler(database.DB)
+	occurrenceHandler := handlers.NewOccurrenceHandler(database.DB)
+	aspirationHandler := handlers.NewAspirationHandler(database.DB)
+	searchHandler := handlers.NewSearchHandler(database.DB)
+	tagHandler := handlers.NewTagHandler(database.DB)
 	// Guest Page
 	router.GET("/api/association", associationHandler.GetAllAssociationsGuest)
 	router.GET("/api/club", clubHandler.GetAllClubsGuest)
 	router.GET("/api/department", departmentHandler.GetAllDepartmentsGuest)
 	router.GET("/api/bems/manage/:period", bemHandler.GetBEMByPeriod)
 
+	// Public iCalendar feeds
+	router.GET("/api/calendar.ics", calendarHandler.GetPublicFeed)
+	router.GET("/api/news", newsHandler.GetPublishedNews)
+	router.GET("/api/news/rss", newsHandler.GetRSSFeed)
+	router.GET("/api/news/atom", newsHandler.GetAtomFeed)
+	router.GET("/api/news/:id", newsHandler.GetPublishedNewsByID)
+	router.GET("/api/news/slug/:slug", newsHandler.GetPublishedNewsBySlug)
+	router.GET("/api/announcements/slug/:slug", announcementHandler.GetAnnouncementBySlug)
+	router.GET("/api/search", searchHandler.Search)
+	router.GET("/api/tags", tagHandler.GetAllTags)
+	router.GET("/api/calendar/organizations/:id", calendarHandler.GetOrganizationFeed)
+
+	// Public borrowing letter verification (QR code target)
+	router.GET("/api/letters/verify/:code", letterHandler.VerifyLetter)
+
 	// Protected routes
 	authRequired := router.Group("/api")
 	authRequired.Use(campus.CampusAuthMiddleware())
@@ -148,6 +197,12 @@ func main() {
 			adminRoutes.PUT("/galery/:id", galeryHandler.UpdateGalery)
 			adminRoutes.DELETE("/galery/:id", galeryHandler.DeleteGalery)
 
+			adminRoutes.GET("/tags", tagHandler.GetAllTags)
+			adminRoutes.POST("/tags", tagHandler.CreateTag)
+			adminRoutes.PUT("/tags/:id", tagHandler.RenameTag)
+			adminRoutes.DELETE("/tags/:id", tagHandler.DeleteTag)
+			adminRoutes.POST("/tags/:id/merge", tagHandler.MergeTag)
+
 			adminRoutes.GET("/department", departmentHandler.GetAllDepartments)
 			adminRoutes.GET("/department/:id", departmentHandler.GetDepartmentByID)
 			adminRoutes.POST("/department", departmentHandler.CreateDepartment)
@@ -155,16 +210,103 @@ func main() {
 			adminRoutes.DELETE("/department/:id", departmentHandler.DeleteDepartment)
 
 			adminRoutes.GET("/request", requestHandler.GetAllRequests)
+			adminRoutes.GET("/request/overdue", requestHandler.GetOverdueRequests)
 			adminRoutes.GET("/request/:id", requestHandler.GetRequestByID)
 			adminRoutes.POST("/request", requestHandler.CreateRequest)
 			adminRoutes.PUT("/request/:id", requestHandler.UpdateRequest)
 			adminRoutes.DELETE("/request/:id", requestHandler.DeleteRequest)
+			adminRoutes.POST("/request/:id/approve", requestHandler.ApproveRequest)
+			adminRoutes.POST("/request/:id/reject", requestHandler.RejectRequest)
+			adminRoutes.POST("/request/:id/cancel", requestHandler.CancelRequest)
+			adminRoutes.POST("/request/:id/handover", requestHandler.HandOverRequest)
+			adminRoutes.POST("/request/:id/return", requestHandler.ReturnRequest)
+			adminRoutes.GET("/request/:id/letter", letterHandler.GetRequestLetter)
+
+			adminRoutes.GET("/activities", activityHandler.GetAllActivities)
+			adminRoutes.GET("/activities/:id", activityHandler.GetActivityByID)
+			adminRoutes.POST("/activities", activityHandler.CreateActivity)
+			adminRoutes.PUT("/activities/:id", activityHandler.UpdateActivity)
+			adminRoutes.DELETE("/activities/:id", activityHandler.DeleteActivity)
+			adminRoutes.GET("/activities/occurrences", occurrenceHandler.GetOccurrences)
+			adminRoutes.GET("/activities/:id/occurrences", occurrenceHandler.GetActivityOccurrences)
+			adminRoutes.PUT("/activities/:id/occurrences", occurrenceHandler.UpdateOccurrence)
+			adminRoutes.POST("/activities/:id/occurrences/cancel", occurrenceHandler.CancelOccurrence)
+			adminRoutes.DELETE("/activities/:id/occurrences", occurrenceHandler.RestoreOccurrence)
+
+			adminRoutes.GET("/activities/:id/proposals", proposalHandler.GetProposalsByActivity)
+			adminRoutes.POST("/activities/:id/proposals", proposalHandler.SubmitProposal)
+			adminRoutes.GET("/proposals/:id", proposalHandler.GetProposalByID)
+			adminRoutes.GET("/proposals/:id/file", proposalHandler.DownloadProposal)
+			adminRoutes.POST("/proposals/:id/review", proposalHandler.ReviewProposal)
+
+			adminRoutes.GET("/activities/:id/report", reportHandler.GetReportByActivity)
+			adminRoutes.GET("/activities/:id/report/file", reportHandler.DownloadReport)
+			adminRoutes.POST("/activities/:id/report", reportHandler.SubmitReport)
+			adminRoutes.GET("/reports/outstanding", reportHandler.GetOutstandingReports)
+
+			adminRoutes.GET("/activities/:id/finances", financeHandler.GetActivityLedger)
+			adminRoutes.POST("/activities/:id/finances", financeHandler.CreateEntry)
+			adminRoutes.PUT("/finances/:id", financeHandler.UpdateEntry)
+			adminRoutes.DELETE("/finances/:id", financeHandler.DeleteEntry)
+			adminRoutes.GET("/finances/summary", financeHandler.GetFinanceSummary)
+
+			adminRoutes.GET("/proposals/:id/budget", budgetHandler.GetBudgetItems)
+			adminRoutes.POST("/proposals/:id/budget", budgetHandler.CreateBudgetItems)
+			adminRoutes.GET("/proposals/:id/budget/report", budgetHandler.GetBudgetReport)
+			adminRoutes.PUT("/budget-items/:id", budgetHandler.UpdateBudgetItem)
+			adminRoutes.DELETE("/budget-items/:id", budgetHandler.DeleteBudgetItem)
+
+			adminRoutes.GET("/activities/:id/registrants", registrationHandler.GetRegistrants)
+			adminRoutes.GET("/activities/:id/registrants/export", registrationHandler.ExportRegistrants)
+			adminRoutes.GET("/activities/:id/checkin-token", attendanceHandler.IssueCheckInToken)
+			adminRoutes.GET("/activities/:id/attendance", attendanceHandler.GetAttendanceReport)
+
+			adminRoutes.GET("/venues", venueHandler.GetAllVenues)
+			adminRoutes.POST("/venues", venueHandler.CreateVenue)
+			adminRoutes.PUT("/venues/:id", venueHandler.UpdateVenue)
+			adminRoutes.DELETE("/venues/:id", venueHandler.DeleteVenue)
+
+			adminRoutes.GET("/inventory", inventoryHandler.GetAllItems)
+			adminRoutes.GET("/inventory/:id", inventoryHandler.GetItemByID)
+			adminRoutes.GET("/inventory/:id/availability", inventoryHandler.GetItemAvailability)
+			adminRoutes.POST("/inventory", inventoryHandler.CreateItem)
+			adminRoutes.PUT("/inventory/:id", inventoryHandler.UpdateItem)
+			adminRoutes.DELETE("/inventory/:id", inventoryHandler.DeleteItem)
+
+			adminRoutes.GET("/aspirations", aspirationHandler.GetAspirationInbox)
+			adminRoutes.GET("/aspirations/trending", aspirationHandler.GetTrendingAspirations)
+			adminRoutes.GET("/aspirations/moderation", aspirationHandler.GetModerationQueue)
+			adminRoutes.GET("/aspirations/:id", aspirationHandler.GetAspirationByID)
+			adminRoutes.POST("/aspirations/:id/reveal-author", aspirationHandler.RevealAspirationAuthor)
+			adminRoutes.POST("/aspirations/:id/status", aspirationHandler.ChangeAspirationStatus)
+			adminRoutes.POST("/aspirations/:id/responses", aspirationHandler.AddAspirationResponse)
+			adminRoutes.POST("/aspirations/:id/merge", aspirationHandler.MergeAspiration)
+			adminRoutes.POST("/aspirations/:id/moderation", aspirationHandler.ModerateAspiration)
 		}
 
 		// Employee routes (replacing assistant routes)
 		studentRoutes := authRequired.Group("/student")
 		studentRoutes.Use(middleware.RoleMiddleware("Mahasiswa"))
 		{
+			studentRoutes.GET("/news", newsHandler.GetPublishedNews)
+			studentRoutes.GET("/news/:id", newsHandler.GetPublishedNewsByID)
+
+			// Konten organisasi untuk pengurus (ketua, wakil ketua, sekretaris) periode berjalan;
+			// kepemilikan konten diperiksa di service
+			studentRoutes.GET("/organization/news", newsHandler.GetAllNews)
+			studentRoutes.GET("/organization/news/:id", newsHandler.GetNewsByID)
+			studentRoutes.POST("/organization/news", newsHandler.CreateNews)
+			studentRoutes.PUT("/organization/news/:id", newsHandler.UpdateNews)
+			studentRoutes.DELETE("/organization/news/:id", newsHandler.DeleteNews)
+			studentRoutes.GET("/organization/galery", galeryHandler.GetAllGalerys)
+			studentRoutes.POST("/organization/galery", galeryHandler.CreateGalery)
+			studentRoutes.PUT("/organization/galery/:id", galeryHandler.UpdateGalery)
+			studentRoutes.DELETE("/organization/galery/:id", galeryHandler.DeleteGalery)
+			studentRoutes.GET("/organization/announcements", announcementHandler.GetAllAnnouncements)
+			studentRoutes.POST("/organization/announcements", announcementHandler.CreateAnnouncement)
+			studentRoutes.PUT("/organization/announcements/:id", announcementHandler.UpdateAnnouncement)
+			studentRoutes.DELETE("/organization/announcements/:id", announcementHandler.DeleteAnnouncement)
+
 			studentRoutes.GET("/clubs", clubHandler.GetAllClubs)
 			studentRoutes.GET("/clubs/:id", clubHandler.GetClubByID)
 
@@ -173,6 +315,52 @@ func main() {
 
 			studentRoutes.GET("/associations", associationHandler.GetAllAssociations)
 			studentRoutes.GET("/associations/:id", associationHandler.GetAssociationByID)
+			studentRoutes.GET("/activities", activityHandler.GetAllActivities)
+			studentRoutes.GET("/activities/:id", activityHandler.GetActivityByID)
+			studentRoutes.GET("/activities/occurrences", occurrenceHandler.GetOccurrences)
+			studentRoutes.GET("/activities/:id/occurrences", occurrenceHandler.GetActivityOccurrences)
+			studentRoutes.GET("/activities/:id/proposals", proposalHandler.GetProposalsByActivity)
+			studentRoutes.POST("/activities/:id/proposals", proposalHandler.SubmitProposal)
+			studentRoutes.GET("/proposals/:id", proposalHandler.GetProposalByID)
+			studentRoutes.GET("/proposals/:id/file", proposalHandler.DownloadProposal)
+			studentRoutes.POST("/proposals/:id/review", proposalHandler.ReviewProposal)
+			studentRoutes.GET("/activities/:id/report", reportHandler.GetReportByActivity)
+			studentRoutes.GET("/activities/:id/report/file", reportHandler.DownloadReport)
+			studentRoutes.POST("/activities/:id/report", reportHandler.SubmitReport)
+			studentRoutes.GET("/activities/:id/finances", financeHandler.GetActivityLedger)
+			studentRoutes.POST("/activities/:id/finances", financeHandler.CreateEntry)
+			studentRoutes.PUT("/finances/:id", financeHandler.UpdateEntry)
+			studentRoutes.DELETE("/finances/:id", financeHandler.DeleteEntry)
+			studentRoutes.GET("/finances/summary", financeHandler.GetFinanceSummary)
+			studentRoutes.GET("/proposals/:id/budget", budgetHandler.GetBudgetItems)
+			studentRoutes.POST("/proposals/:id/budget", budgetHandler.CreateBudgetItems)
+			studentRoutes.GET("/proposals/:id/budget/report", budgetHandler.GetBudgetReport)
+			studentRoutes.PUT("/budget-items/:id", budgetHandler.UpdateBudgetItem)
+			studentRoutes.DELETE("/budget-items/:id", budgetHandler.DeleteBudgetItem)
+			studentRoutes.POST("/activities/:id/register", registrationHandler.RegisterActivity)
+			studentRoutes.DELETE("/activities/:id/register", registrationHandler.CancelRegistration)
+			studentRoutes.GET("/registrations", registrationHandler.GetMyRegistrations)
+			studentRoutes.GET("/activities/:id/registrants", registrationHandler.GetRegistrants)
+			studentRoutes.GET("/activities/:id/registrants/export", registrationHandler.ExportRegistrants)
+			studentRoutes.GET("/activities/:id/checkin-token", attendanceHandler.IssueCheckInToken)
+			studentRoutes.GET("/activities/:id/attendance", attendanceHandler.GetAttendanceReport)
+			studentRoutes.POST("/checkin", attendanceHandler.CheckIn)
+			studentRoutes.GET("/venues", venueHandler.GetAllVenues)
+			studentRoutes.GET("/inventory", inventoryHandler.GetAllItems)
+			studentRoutes.GET("/inventory/:id/availability", inventoryHandler.GetItemAvailability)
+			studentRoutes.GET("/request", requestHandler.GetMyRequests)
+			studentRoutes.GET("/request/:id", requestHandler.GetMyRequestByID)
+			studentRoutes.POST("/request", requestHandler.CreateRequest)
+			studentRoutes.PUT("/request/:id", requestHandler.UpdateMyRequest)
+			studentRoutes.POST("/request/:id/cancel", requestHandler.CancelMyRequest)
+			studentRoutes.GET("/request/:id/letter", letterHandler.GetMyRequestLetter)
+			studentRoutes.POST("/aspirations", aspirationHandler.SubmitAspiration)
+			studentRoutes.GET("/aspirations/mine", aspirationHandler.GetMyAspirations)
+			studentRoutes.GET("/aspirations/mine/:id", aspirationHandler.GetMyAspirationByID)
+			studentRoutes.GET("/aspirations/trending", aspirationHandler.GetTrendingAspirations)
+			studentRoutes.POST("/aspirations/:id/vote", aspirationHandler.VoteAspiration)
+			studentRoutes.DELETE("/aspirations/:id/vote", aspirationHandler.UnvoteAspiration)
+
 			studentRoutes.GET("/profile", handlers.GetCurrentUser)
 			studentRoutes.PUT("/profile", handlers.EditProfile)
 		}
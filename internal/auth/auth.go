@@ -110,8 +110,9 @@ func ValidateToken(tokenString string) (*Claims, error) {
 		return nil, err
 	}
 
-	// Extract the claims
-	if claims, ok := token.Claims.(*Claims); ok && token.Valid {
+	// Extract the claims. Login tokens carry no audience; tokens issued for other
+	// purposes (e.g. activity check-in QR codes) are rejected.
+	if claims, ok := token.Claims.(*Claims); ok && token.Valid && claims.Audience == "" {
 		return claims, nil
 	}
 
@@ -47,6 +47,8 @@ func Initialize() {
 	DB, err = gorm.Open(mysql.Open(dsn), &gorm.Config{
 		Logger:                                   newLogger,
 		DisableForeignKeyConstraintWhenMigrating: true,
+		// Translate driver errors such as duplicate keys into gorm.ErrDuplicatedKey
+		TranslateError: true,
 	})
 	if err != nil {
 		log.Fatalf("Error connecting to database: %v", err)
@@ -104,6 +106,13 @@ func Initialize() {
 	}
 	log.Println("BEM table migrated successfully")
 
+	// Tag dipakai oleh kegiatan, galeri, dan berita
+	err = DB.AutoMigrate(&models.Tag{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating Tag model: %v\n", err)
+	}
+	log.Println("Tag table migrated successfully")
+
 	// Aktivitas
 	err = DB.AutoMigrate(&models.Activity{})
 	if err != nil {
@@ -111,12 +120,57 @@ func Initialize() {
 	}
 	log.Println("Activity table migrated successfully")
 
+	err = DB.AutoMigrate(&models.ActivityOccurrence{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating ActivityOccurrence model: %v\n", err)
+	}
+	log.Println("ActivityOccurrence table migrated successfully")
+
+	err = DB.AutoMigrate(&models.Venue{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating Venue model: %v\n", err)
+	}
+	log.Println("Venue table migrated successfully")
+
+	err = DB.AutoMigrate(&models.ActivityRegistration{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating ActivityRegistration model: %v\n", err)
+	}
+	log.Println("ActivityRegistration table migrated successfully")
+
+	// The attendance unique index used to cover only (activity_id, student_id); drop it so
+	// students can check in once per occurrence under idx_attendance_activity_occurrence_student
+	if DB.Migrator().HasIndex(&models.ActivityAttendance{}, "idx_attendance_activity_student") {
+		err = DB.Migrator().DropIndex(&models.ActivityAttendance{}, "idx_attendance_activity_student")
+		if err != nil {
+			log.Fatalf("Error dropping old ActivityAttendance index: %v\n", err)
+		}
+	}
+
+	err = DB.AutoMigrate(&models.ActivityAttendance{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating ActivityAttendance model: %v\n", err)
+	}
+	log.Println("ActivityAttendance table migrated successfully")
+
 	err = DB.AutoMigrate(&models.Proposal{})
 	if err != nil {
 		log.Fatalf("Error auto-migrating Proposal model: %v\n", err)
 	}
 	log.Println("Proposal table migrated successfully")
 
+	err = DB.AutoMigrate(&models.ProposalReview{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating ProposalReview model: %v\n", err)
+	}
+	log.Println("ProposalReview table migrated successfully")
+
+	err = DB.AutoMigrate(&models.BudgetItem{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating BudgetItem model: %v\n", err)
+	}
+	log.Println("BudgetItem table migrated successfully")
+
 	err = DB.AutoMigrate(&models.Report{})
 	if err != nil {
 		log.Fatalf("Error auto-migrating Report model: %v\n", err)
@@ -129,6 +183,12 @@ func Initialize() {
 	}
 	log.Println("Finance table migrated successfully")
 
+	err = DB.AutoMigrate(&models.FinanceEntry{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating FinanceEntry model: %v\n", err)
+	}
+	log.Println("FinanceEntry table migrated successfully")
+
 	// Umum
 	err = DB.AutoMigrate(&models.News{})
 	if err != nil {
@@ -136,6 +196,23 @@ func Initialize() {
 	}
 	log.Println("News table migrated successfully")
 
+	err = migrateNewsCategories(DB)
+	if err != nil {
+		log.Fatalf("Error converting news categories into tags: %v\n", err)
+	}
+
+	err = DB.AutoMigrate(&models.Announcement{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating Announcement model: %v\n", err)
+	}
+	log.Println("Announcement table migrated successfully")
+
+	err = DB.AutoMigrate(&models.SlugRedirect{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating SlugRedirect model: %v\n", err)
+	}
+	log.Println("SlugRedirect table migrated successfully")
+
 	log.Println("Database schema migrated successfully")
 
 	err = DB.AutoMigrate(&models.Aspiration{})
@@ -144,6 +221,30 @@ func Initialize() {
 	}
 	log.Println("Aspiration table migrated successfully")
 
+	err = DB.AutoMigrate(&models.AspirationResponse{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating AspirationResponse model: %v\n", err)
+	}
+	log.Println("AspirationResponse table migrated successfully")
+
+	err = DB.AutoMigrate(&models.AspirationStatusHistory{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating AspirationStatusHistory model: %v\n", err)
+	}
+	log.Println("AspirationStatusHistory table migrated successfully")
+
+	err = DB.AutoMigrate(&models.AspirationVote{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating AspirationVote model: %v\n", err)
+	}
+	log.Println("AspirationVote table migrated successfully")
+
+	err = DB.AutoMigrate(&models.AspirationAuthorAccess{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating AspirationAuthorAccess model: %v\n", err)
+	}
+	log.Println("AspirationAuthorAccess table migrated successfully")
+
 	log.Println("Database schema migrated successfully")
 
 	err = DB.AutoMigrate(&models.Galery{})
@@ -152,12 +253,36 @@ func Initialize() {
 	}
 	log.Println("Galery table migrated successfully")
 
+	err = DB.AutoMigrate(&models.InventoryItem{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating InventoryItem model: %v\n", err)
+	}
+	log.Println("InventoryItem table migrated successfully")
+
 	err = DB.AutoMigrate(&models.Request{})
 	if err != nil {
 		log.Fatalf("Error auto-migrating Request model: %v\n", err)
 	}
 	log.Println("Request table migrated successfully")
 
+	err = DB.AutoMigrate(&models.BorrowingLetter{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating BorrowingLetter model: %v\n", err)
+	}
+	log.Println("BorrowingLetter table migrated successfully")
+
+	err = DB.AutoMigrate(&models.LetterSequence{})
+	if err != nil {
+		log.Fatalf("Error auto-migrating LetterSequence model: %v\n", err)
+	}
+	log.Println("LetterSequence table migrated successfully")
+
+	// Search falls back to its in-memory index when the FULLTEXT indexes are missing
+	err = ensureFullTextIndexes(DB)
+	if err != nil {
+		log.Printf("Warning: full-text search indexes unavailable: %v\n", err)
+	}
+
 	log.Println("Database schema migrated successfully")
 
 }
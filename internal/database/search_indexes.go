@@ -0,0 +1,52 @@
+package database
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// FullTextIndex is a MySQL FULLTEXT index used by the database search backend.
+// Search queries must MATCH exactly the indexed columns.
+type FullTextIndex struct {
+	Name    string
+	Table   string
+	Columns []string
+}
+
+// FULLTEXT indexes for site search, one per searchable content type
+var (
+	NewsSearchIndex         = FullTextIndex{Name: "ft_news_search", Table: "news", Columns: []string{"title", "content"}}
+	AnnouncementSearchIndex = FullTextIndex{Name: "ft_announcements_search", Table: "announcements", Columns: []string{"title", "content"}}
+	GallerySearchIndex      = FullTextIndex{Name: "ft_galery_search", Table: "galery", Columns: []string{"title", "content"}}
+	OrganizationSearchIndex = FullTextIndex{Name: "ft_organizations_search", Table: "organizations", Columns: []string{"name", "short_name"}}
+	ActivitySearchIndex     = FullTextIndex{Name: "ft_activities_search", Table: "activities", Columns: []string{"title", "description", "location"}}
+	SearchIndexes           = []FullTextIndex{NewsSearchIndex, AnnouncementSearchIndex, GallerySearchIndex, OrganizationSearchIndex, ActivitySearchIndex}
+)
+
+// ensureFullTextIndexes creates the FULLTEXT indexes used by search that do not exist yet
+func ensureFullTextIndexes(db *gorm.DB) error {
+	for _, index := range SearchIndexes {
+		if db.Migrator().HasIndex(index.Table, index.Name) {
+			continue
+		}
+		sql := fmt.Sprintf("CREATE FULLTEXT INDEX %s ON %s (%s)", index.Name, index.Table, strings.Join(index.Columns, ", "))
+		if err := db.Exec(sql).Error; err != nil {
+			return fmt.Errorf("failed to create index %s: %w", index.Name, err)
+		}
+		log.Printf("Full-text index %s created", index.Name)
+	}
+	return nil
+}
+
+// HasFullTextIndexes reports whether every FULLTEXT index used by search exists
+func HasFullTextIndexes(db *gorm.DB) bool {
+	for _, index := range SearchIndexes {
+		if !db.Migrator().HasIndex(index.Table, index.Name) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+package database
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"bem_be/internal/models"
+	"bem_be/internal/utils"
+
+	"gorm.io/gorm"
+)
+
+// migrateNewsCategories converts the legacy free-text news.category column into
+// tags. Converted rows have their category cleared so the conversion is not
+// repeated on the next start. The column is only dropped once every category has
+// been converted; categories that cannot become a tag are kept and logged.
+// Categories that only differ in case or punctuation end up as the same tag.
+func migrateNewsCategories(db *gorm.DB) error {
+	if !db.Migrator().HasColumn(&models.News{}, "category") {
+		return nil
+	}
+
+	type legacyCategory struct {
+		ID       uint
+		Category string
+	}
+	var rows []legacyCategory
+	err := db.Table("news").Select("id, category").
+		Where("category IS NOT NULL AND category <> ''").Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		tagIDs := make(map[string]uint)
+		var converted, skipped []uint
+		for _, row := range rows {
+			name := strings.TrimSpace(row.Category)
+			slug := utils.Slugify(name)
+			if slug == "" {
+				skipped = append(skipped, row.ID)
+				continue
+			}
+
+			tagID, ok := tagIDs[slug]
+			if !ok {
+				var tag models.Tag
+				err := tx.Where("slug = ?", slug).First(&tag).Error
+				if errors.Is(err, gorm.ErrRecordNotFound) {
+					tag = models.Tag{Name: name, Slug: slug}
+					err = tx.Create(&tag).Error
+				}
+				if err != nil {
+					return err
+				}
+				tagID = tag.ID
+				tagIDs[slug] = tagID
+			}
+
+			if err := tx.Exec("INSERT IGNORE INTO news_tags (news_id, tag_id) VALUES (?, ?)", row.ID, tagID).Error; err != nil {
+				return err
+			}
+			converted = append(converted, row.ID)
+		}
+
+		if len(converted) > 0 {
+			if err := tx.Table("news").Where("id IN ?", converted).UpdateColumn("category", "").Error; err != nil {
+				return err
+			}
+		}
+		log.Printf("Converted %d news categories into %d tags", len(converted), len(tagIDs))
+		if len(skipped) > 0 {
+			log.Printf("Keeping news.category: %d news have categories that cannot be converted into tags (ids %v)", len(skipped), skipped)
+			return nil
+		}
+		return tx.Migrator().DropColumn(&models.News{}, "category")
+	})
+}
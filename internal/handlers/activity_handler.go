@@ -0,0 +1,300 @@
+package handlers
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"bem_be/internal/services"
+	"bem_be/internal/utils"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ActivityHandler menangani request HTTP terkait kegiatan
+type ActivityHandler struct {
+	service *services.ActivityService
+}
+
+// NewActivityHandler membuat handler kegiatan baru
+func NewActivityHandler(db *gorm.DB) *ActivityHandler {
+	return &ActivityHandler{
+		service: services.NewActivityService(db),
+	}
+}
+
+// Format tanggal yang diterima dari form maupun query string
+var dateTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// Helper untuk parsing tanggal dari form data atau query string
+func parseDateTime(value string) (time.Time, error) {
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, errors.New("format tanggal tidak valid: " + value)
+}
+
+// Helper untuk parsing tanggal opsional dari query string
+func parseOptionalDateTime(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := parseDateTime(value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// bindActivityForm mengisi field kegiatan dari form data
+func bindActivityForm(c *gin.Context, activity *models.Activity) error {
+	activity.Title = c.PostForm("title")
+	activity.Description = c.PostForm("description")
+	activity.Location = c.PostForm("location")
+	activity.DepartmentID = parseOptionalUint(c.PostForm("department_id"))
+	activity.AssociationID = parseOptionalUint(c.PostForm("association_id"))
+	activity.BEMID = parseOptionalUint(c.PostForm("bem_id"))
+	if tags, ok := bindTagForm(c); ok {
+		activity.Tags = tags
+	}
+
+	if capacityStr := c.PostForm("capacity"); capacityStr != "" {
+		capacity, err := strconv.Atoi(capacityStr)
+		if err != nil || capacity < 0 {
+			return errors.New("kapasitas tidak valid")
+		}
+		activity.Capacity = capacity
+	}
+
+	startDate, err := parseDateTime(c.PostForm("start_date"))
+	if err != nil {
+		return err
+	}
+	endDate, err := parseDateTime(c.PostForm("end_date"))
+	if err != nil {
+		return err
+	}
+	activity.StartDate = startDate
+	activity.EndDate = endDate
+
+	return bindRecurrenceForm(c, activity)
+}
+
+// bindRecurrenceForm mengisi aturan pengulangan kegiatan dari form data
+func bindRecurrenceForm(c *gin.Context, activity *models.Activity) error {
+	activity.RecurrenceFrequency = strings.ToLower(strings.TrimSpace(c.PostForm("recurrence_frequency")))
+	activity.RecurrenceInterval = 1
+	activity.RecurrenceCount = 0
+
+	if intervalStr := c.PostForm("recurrence_interval"); intervalStr != "" {
+		interval, err := strconv.Atoi(intervalStr)
+		if err != nil || interval < 1 {
+			return errors.New("interval pengulangan tidak valid")
+		}
+		activity.RecurrenceInterval = interval
+	}
+	if countStr := c.PostForm("recurrence_count"); countStr != "" {
+		count, err := strconv.Atoi(countStr)
+		if err != nil || count < 0 {
+			return errors.New("jumlah pengulangan tidak valid")
+		}
+		activity.RecurrenceCount = count
+	}
+
+	until, err := parseOptionalDateTime(c.PostForm("recurrence_until"))
+	if err != nil {
+		return err
+	}
+	activity.RecurrenceUntil = until
+	return nil
+}
+
+// respondActivityError mengirim error simpan kegiatan, termasuk daftar bentrok untuk lokasi eksklusif
+func respondActivityError(c *gin.Context, err error, conflicts []services.ActivityConflict) {
+	if errors.Is(err, services.ErrVenueConflict) {
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error(), "conflicts": conflicts})
+		return
+	}
+	if errors.Is(err, services.ErrOccurrenceHasData) {
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+}
+
+// activityConflictMessage menambahkan peringatan bentrok jadwal ke pesan sukses
+func activityConflictMessage(message string, conflicts []services.ActivityConflict) string {
+	if len(conflicts) == 0 {
+		return message
+	}
+	return fmt.Sprintf("%s, namun jadwal bentrok dengan %d kegiatan lain di lokasi yang sama", message, len(conflicts))
+}
+
+// GetAllActivities mengembalikan daftar kegiatan dengan filter dan pagination
+func (h *ActivityHandler) GetAllActivities(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+
+	offset := (page - 1) * perPage
+
+	from, err := parseOptionalDateTime(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+	to, err := parseOptionalDateTime(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	filter := repositories.ActivityFilter{
+		DepartmentID:  parseOptionalUint(c.Query("department_id")),
+		AssociationID: parseOptionalUint(c.Query("association_id")),
+		BEMID:         parseOptionalUint(c.Query("bem_id")),
+		From:          from,
+		To:            to,
+		Tag:           c.Query("tag"),
+	}
+
+	activities, total, err := h.service.GetAllActivities(filter, perPage, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ResponseHandler("error", err.Error(), nil))
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(perPage)))
+
+	metadata := utils.PaginationMetadata{
+		CurrentPage: page,
+		PerPage:     perPage,
+		TotalItems:  int(total),
+		TotalPages:  totalPages,
+		Links: utils.PaginationLinks{
+			First: fmt.Sprintf("/activities?page=1&per_page=%d%s", perPage, tagQuery(filter.Tag)),
+			Last:  fmt.Sprintf("/activities?page=%d&per_page=%d%s", totalPages, perPage, tagQuery(filter.Tag)),
+		},
+	}
+
+	response := utils.MetadataFormatResponse(
+		"success",
+		"Berhasil mendapatkan daftar kegiatan",
+		metadata,
+		activities,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetActivityByID mengembalikan kegiatan berdasarkan ID
+func (h *ActivityHandler) GetActivityByID(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	activity, err := h.service.GetActivityByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Kegiatan berhasil didapatkan",
+		"data":    activity,
+	})
+}
+
+// CreateActivity membuat kegiatan baru
+func (h *ActivityHandler) CreateActivity(c *gin.Context) {
+	var activity models.Activity
+	if err := bindActivityForm(c, &activity); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	conflicts, err := h.service.CreateActivity(&activity)
+	if err != nil {
+		respondActivityError(c, err, conflicts)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":    "success",
+		"message":   activityConflictMessage("Kegiatan berhasil dibuat", conflicts),
+		"data":      activity,
+		"conflicts": conflicts,
+	})
+}
+
+// UpdateActivity memperbarui kegiatan yang ada
+func (h *ActivityHandler) UpdateActivity(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	existingActivity, err := h.service.GetActivityByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	if err := bindActivityForm(c, existingActivity); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	conflicts, err := h.service.UpdateActivity(existingActivity)
+	if err != nil {
+		respondActivityError(c, err, conflicts)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   activityConflictMessage("Kegiatan berhasil diperbarui", conflicts),
+		"data":      existingActivity,
+		"conflicts": conflicts,
+	})
+}
+
+// DeleteActivity menghapus sebuah kegiatan
+func (h *ActivityHandler) DeleteActivity(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteActivity(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Kegiatan berhasil dihapus",
+	})
+}
@@ -20,17 +20,25 @@ import (
 // AnnouncementHandler handles HTTP requests related to announcements
 type AnnouncementHandler struct {
 	service *services.AnnouncementService
+	authors *services.AuthorService
 }
 
 // NewAnnouncementHandler creates a new announcement handler
 func NewAnnouncementHandler(db *gorm.DB) *AnnouncementHandler {
 	return &AnnouncementHandler{
 		service: services.NewAnnouncementService(db),
+		authors: services.NewAuthorService(db),
 	}
 }
 
-// GetAllAnnouncements returns all announcements
+// GetAllAnnouncements returns all announcements. Organization officers only get the
+// announcements of their own organizations
 func (h *AnnouncementHandler) GetAllAnnouncements(c *gin.Context) {
+	author, ok := resolveAuthor(c, h.authors)
+	if !ok {
+		return
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
 
@@ -43,9 +51,9 @@ func (h *AnnouncementHandler) GetAllAnnouncements(c *gin.Context) {
 
 	offset := (page - 1) * perPage
 
-	announcements, total, err := h.service.GetAllAnnouncements(perPage, offset)
+	announcements, total, err := h.service.GetManagedAnnouncements(author, perPage, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.ResponseHandler("error", err.Error(), nil))
+		c.JSON(authorErrorStatus(err, http.StatusInternalServerError), utils.ResponseHandler("error", err.Error(), nil))
 		return
 	}
 
@@ -94,12 +102,34 @@ func (h *AnnouncementHandler) GetAnnouncementByID(c *gin.Context) {
 	})
 }
 
+// GetAnnouncementBySlug returns an announcement by slug, redirecting old slugs to the current one
+func (h *AnnouncementHandler) GetAnnouncementBySlug(c *gin.Context) {
+	announcement, err := h.service.GetAnnouncementBySlug(c.Param("slug"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Announcement not found"})
+		return
+	}
+	if announcement.Slug != c.Param("slug") {
+		redirectToSlug(c, announcement.Slug)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Announcement retrieved successfully",
+		"data":    announcement,
+	})
+}
+
 // CreateAnnouncement creates a new announcement (with optional file)
 func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
 	var announcement models.Announcement
 
 	announcement.Title = c.PostForm("title")
 	announcement.Content = c.PostForm("content")
+	announcement.BEMID = parseOptionalUint(c.PostForm("bem_id"))
+	announcement.AssociationID = parseOptionalUint(c.PostForm("association_id"))
+	announcement.DepartmentID = parseOptionalUint(c.PostForm("department_id"))
 	userID, exists := c.Get("userID")
     if !exists {
         c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
@@ -107,6 +137,11 @@ func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
     }
     announcement.AuthorID = userID.(uint)
 
+	author, ok := resolveAuthor(c, h.authors)
+	if !ok {
+		return
+	}
+
 	// Handle file upload
 	file, err := c.FormFile("file")
 	if err == nil {
@@ -130,8 +165,9 @@ func (h *AnnouncementHandler) CreateAnnouncement(c *gin.Context) {
 		announcement.FileURL = filePath
 	}
 
-	if err := h.service.Createannouncement(&announcement); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.Createannouncement(&announcement, author); err != nil {
+		discardUpload(announcement.FileURL)
+		c.JSON(authorErrorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -151,10 +187,22 @@ func (h *AnnouncementHandler) UpdateAnnouncement(c *gin.Context) {
 		return
 	}
 
+	author, ok := resolveAuthor(c, h.authors)
+	if !ok {
+		return
+	}
+	if _, err := h.service.GetManagedAnnouncementByID(uint(id), author); err != nil {
+		c.JSON(authorErrorStatus(err, http.StatusNotFound), gin.H{"error": err.Error()})
+		return
+	}
+
 	var announcement models.Announcement
 	announcement.ID = uint(id)
 	announcement.Title = c.PostForm("title")
 	announcement.Content = c.PostForm("content")
+	announcement.BEMID = parseOptionalUint(c.PostForm("bem_id"))
+	announcement.AssociationID = parseOptionalUint(c.PostForm("association_id"))
+	announcement.DepartmentID = parseOptionalUint(c.PostForm("department_id"))
 
 	file, err := c.FormFile("file")
 	if err == nil {
@@ -175,8 +223,9 @@ func (h *AnnouncementHandler) UpdateAnnouncement(c *gin.Context) {
 		announcement.FileURL = filePath
 	}
 
-	if err := h.service.Updateannouncement(&announcement); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.Updateannouncement(&announcement, author); err != nil {
+		discardUpload(announcement.FileURL)
+		c.JSON(authorErrorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -196,8 +245,13 @@ func (h *AnnouncementHandler) DeleteAnnouncement(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteAnnouncement(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	author, ok := resolveAuthor(c, h.authors)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteAnnouncement(uint(id), author); err != nil {
+		c.JSON(authorErrorStatus(err, http.StatusInternalServerError), gin.H{"error": err.Error()})
 		return
 	}
 
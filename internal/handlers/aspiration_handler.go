@@ -0,0 +1,519 @@
+package handlers
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"bem_be/internal/services"
+	"bem_be/internal/utils"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AspirationHandler menangani request HTTP terkait aspirasi mahasiswa
+type AspirationHandler struct {
+	service *services.AspirationService
+}
+
+// NewAspirationHandler membuat handler aspirasi baru
+func NewAspirationHandler(db *gorm.DB) *AspirationHandler {
+	return &AspirationHandler{
+		service: services.NewAspirationService(db),
+	}
+}
+
+// SubmitAspiration menyimpan aspirasi dari mahasiswa yang login
+func (h *AspirationHandler) SubmitAspiration(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	anonymous, _ := strconv.ParseBool(c.DefaultPostForm("anonymous", "false"))
+	aspiration := models.Aspiration{
+		Title:          c.PostForm("title"),
+		Content:        c.PostForm("content"),
+		Category:       c.PostForm("category"),
+		OrganizationID: parseOptionalUint(c.PostForm("organization_id")),
+		IsAnonymous:    anonymous,
+	}
+
+	if err := h.service.SubmitAspiration(&aspiration, userID); err != nil {
+		if errors.Is(err, services.ErrAspirationRateLimited) {
+			c.JSON(http.StatusTooManyRequests, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	message := "Aspirasi berhasil dikirim"
+	if aspiration.Moderation == models.AspirationModerationPending {
+		message = "Aspirasi berhasil dikirim dan menunggu moderasi admin"
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": message,
+		"data":    aspiration,
+	})
+}
+
+// GetAspirationInbox mengembalikan kotak masuk aspirasi dengan filter dan pagination
+func (h *AspirationHandler) GetAspirationInbox(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+
+	offset := (page - 1) * perPage
+
+	from, err := parseOptionalDateTime(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+	to, err := parseOptionalDateTime(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	filter := repositories.AspirationFilter{
+		Status:         c.Query("status"),
+		Moderation:     c.Query("moderation_status"),
+		Category:       c.Query("category"),
+		OrganizationID: parseOptionalUint(c.Query("organization_id")),
+		Search:         c.Query("q"),
+		From:           from,
+		To:             to,
+	}
+	if anonymousStr := c.Query("anonymous"); anonymousStr != "" {
+		anonymous, err := strconv.ParseBool(anonymousStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Parameter anonymous tidak valid"})
+			return
+		}
+		filter.Anonymous = &anonymous
+	}
+
+	aspirations, total, err := h.service.GetInbox(filter, perPage, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ResponseHandler("error", err.Error(), nil))
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(perPage)))
+
+	metadata := utils.PaginationMetadata{
+		CurrentPage: page,
+		PerPage:     perPage,
+		TotalItems:  int(total),
+		TotalPages:  totalPages,
+		Links: utils.PaginationLinks{
+			First: fmt.Sprintf("/aspirations?page=1&per_page=%d", perPage),
+			Last:  fmt.Sprintf("/aspirations?page=%d&per_page=%d", totalPages, perPage),
+		},
+	}
+
+	response := utils.MetadataFormatResponse(
+		"success",
+		"Berhasil mendapatkan daftar aspirasi",
+		metadata,
+		aspirations,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetModerationQueue mengembalikan antrean aspirasi yang ditandai filter konten
+func (h *AspirationHandler) GetModerationQueue(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+
+	offset := (page - 1) * perPage
+
+	aspirations, total, err := h.service.GetModerationQueue(perPage, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ResponseHandler("error", err.Error(), nil))
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(perPage)))
+
+	metadata := utils.PaginationMetadata{
+		CurrentPage: page,
+		PerPage:     perPage,
+		TotalItems:  int(total),
+		TotalPages:  totalPages,
+		Links: utils.PaginationLinks{
+			First: fmt.Sprintf("/aspirations/moderation?page=1&per_page=%d", perPage),
+			Last:  fmt.Sprintf("/aspirations/moderation?page=%d&per_page=%d", totalPages, perPage),
+		},
+	}
+
+	response := utils.MetadataFormatResponse(
+		"success",
+		"Berhasil mendapatkan antrean moderasi aspirasi",
+		metadata,
+		aspirations,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ModerateAspiration menyetujui, menyembunyikan, atau menandai aspirasi sebagai penyalahgunaan
+func (h *AspirationHandler) ModerateAspiration(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Action string `json:"action" form:"action" binding:"required"`
+		Note   string `json:"note" form:"note"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Aksi moderasi wajib diisi (approve, hide, abusive)"})
+		return
+	}
+
+	aspiration, err := h.service.Moderate(id, input.Action, input.Note, adminID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidModerationAction) {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Moderasi aspirasi berhasil disimpan",
+		"data":    aspiration,
+	})
+}
+
+// GetAspirationByID mengembalikan aspirasi berdasarkan ID
+func (h *AspirationHandler) GetAspirationByID(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	aspiration, err := h.service.GetAspirationByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Aspirasi berhasil didapatkan",
+		"data":    aspiration,
+	})
+}
+
+// RevealAspirationAuthor membuka identitas pengirim aspirasi untuk penanganan penyalahgunaan
+func (h *AspirationHandler) RevealAspirationAuthor(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Reason string `json:"reason" form:"reason"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	student, err := h.service.RevealAuthor(id, adminID, input.Reason)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Identitas pengirim aspirasi berhasil dibuka",
+		"data":    student,
+	})
+}
+
+// ChangeAspirationStatus memindahkan status aspirasi
+func (h *AspirationHandler) ChangeAspirationStatus(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Status string `json:"status" form:"status" binding:"required"`
+		Note   string `json:"note" form:"note"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	aspiration, err := h.service.ChangeStatus(id, input.Status, input.Note, adminID)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidAspirationTransition) {
+			c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Status aspirasi berhasil diperbarui",
+		"data":    aspiration,
+	})
+}
+
+// AddAspirationResponse menambahkan tanggapan resmi ke utas aspirasi
+func (h *AspirationHandler) AddAspirationResponse(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Content string `json:"content" form:"content" binding:"required"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	response, err := h.service.AddResponse(id, input.Content, adminID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Tanggapan berhasil ditambahkan",
+		"data":    response,
+	})
+}
+
+// GetMyAspirations mengembalikan aspirasi milik mahasiswa yang login
+func (h *AspirationHandler) GetMyAspirations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+
+	offset := (page - 1) * perPage
+
+	aspirations, total, err := h.service.GetMyAspirations(userID, perPage, offset)
+	if err != nil {
+		c.JSON(http.StatusNotFound, utils.ResponseHandler("error", err.Error(), nil))
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(perPage)))
+
+	metadata := utils.PaginationMetadata{
+		CurrentPage: page,
+		PerPage:     perPage,
+		TotalItems:  int(total),
+		TotalPages:  totalPages,
+		Links: utils.PaginationLinks{
+			First: fmt.Sprintf("/aspirations/mine?page=1&per_page=%d", perPage),
+			Last:  fmt.Sprintf("/aspirations/mine?page=%d&per_page=%d", totalPages, perPage),
+		},
+	}
+
+	response := utils.MetadataFormatResponse(
+		"success",
+		"Berhasil mendapatkan daftar aspirasi anda",
+		metadata,
+		aspirations,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMyAspirationByID mengembalikan aspirasi milik mahasiswa yang login beserta tanggapan dan riwayat statusnya
+func (h *AspirationHandler) GetMyAspirationByID(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	aspiration, err := h.service.GetMyAspirationByID(id, userID)
+	if err != nil {
+		if errors.Is(err, services.ErrAspirationForbidden) {
+			c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Aspirasi berhasil didapatkan",
+		"data":    aspiration,
+	})
+}
+
+// aspirationVoteErrorStatus memetakan error dukungan aspirasi ke HTTP status
+func aspirationVoteErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrAlreadyVoted), errors.Is(err, services.ErrInvalidAspirationMerge):
+		return http.StatusConflict
+	default:
+		return http.StatusNotFound
+	}
+}
+
+// VoteAspiration mencatat dukungan mahasiswa yang login pada aspirasi
+func (h *AspirationHandler) VoteAspiration(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Vote(id, userID)
+	if err != nil {
+		c.JSON(aspirationVoteErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Dukungan aspirasi berhasil dicatat",
+		"data":    result,
+	})
+}
+
+// UnvoteAspiration membatalkan dukungan mahasiswa yang login pada aspirasi
+func (h *AspirationHandler) UnvoteAspiration(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	result, err := h.service.Unvote(id, userID)
+	if err != nil {
+		c.JSON(aspirationVoteErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Dukungan aspirasi berhasil dibatalkan",
+		"data":    result,
+	})
+}
+
+// GetTrendingAspirations mengembalikan aspirasi yang paling banyak didukung akhir-akhir ini
+func (h *AspirationHandler) GetTrendingAspirations(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if limit < 1 {
+		limit = 10
+	}
+
+	trending, err := h.service.GetTrending(limit, isAdminRole(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, utils.ResponseHandler("error", err.Error(), nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan aspirasi trending",
+		"data":    trending,
+	})
+}
+
+// MergeAspiration menggabungkan aspirasi duplikat ke aspirasi tujuan
+func (h *AspirationHandler) MergeAspiration(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	var input struct {
+		TargetID uint `json:"target_id" form:"target_id" binding:"required"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "ID aspirasi tujuan wajib diisi"})
+		return
+	}
+
+	aspiration, err := h.service.MergeAspiration(id, input.TargetID)
+	if err != nil {
+		c.JSON(aspirationVoteErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Aspirasi berhasil digabungkan",
+		"data":    aspiration,
+	})
+}
@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"bem_be/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// AttendanceHandler menangani request HTTP terkait presensi kegiatan
+type AttendanceHandler struct {
+	service *services.AttendanceService
+}
+
+// NewAttendanceHandler membuat handler presensi baru
+func NewAttendanceHandler(db *gorm.DB) *AttendanceHandler {
+	return &AttendanceHandler{
+		service: services.NewAttendanceService(db),
+	}
+}
+
+// attendanceErrorStatus memetakan error service presensi ke HTTP status
+func attendanceErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrNotActivityOrganizer):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrAlreadyCheckedIn):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidCheckInToken), errors.Is(err, services.ErrCheckInClosed):
+		return http.StatusBadRequest
+	default:
+		return http.StatusNotFound
+	}
+}
+
+// IssueCheckInToken menerbitkan token presensi yang ditampilkan frontend sebagai QR code
+func (h *AttendanceHandler) IssueCheckInToken(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	token, err := h.service.IssueToken(activityID, userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(attendanceErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Token presensi berhasil dibuat",
+		"data":    token,
+	})
+}
+
+// CheckIn mencatat presensi mahasiswa yang login dari hasil pindaian QR code
+func (h *AttendanceHandler) CheckIn(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Token string `json:"token" form:"token" binding:"required"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Token presensi wajib diisi"})
+		return
+	}
+
+	attendance, err := h.service.CheckIn(input.Token, userID)
+	if err != nil {
+		c.JSON(attendanceErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Presensi berhasil dicatat",
+		"data":    attendance,
+	})
+}
+
+// GetAttendanceReport mengembalikan rekap presensi sebuah kegiatan, opsional per pertemuan
+func (h *AttendanceHandler) GetAttendanceReport(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	occurrenceStart, err := parseOptionalDateTime(c.Query("occurrence_start"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	report, err := h.service.GetAttendanceReport(activityID, occurrenceStart, userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(attendanceErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Rekap presensi berhasil didapatkan",
+		"data":    report,
+	})
+}
@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/services"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// BudgetHandler menangani request HTTP terkait RAB proposal
+type BudgetHandler struct {
+	service *services.BudgetService
+}
+
+// NewBudgetHandler membuat handler RAB baru
+func NewBudgetHandler(db *gorm.DB) *BudgetHandler {
+	return &BudgetHandler{
+		service: services.NewBudgetService(db),
+	}
+}
+
+// budgetErrorStatus memetakan error RAB ke status HTTP
+func budgetErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrBudgetLocked):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrNotActivityOrganizer):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrProposalNotFound), errors.Is(err, services.ErrActivityNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// GetBudgetItems mengembalikan semua baris RAB milik proposal
+func (h *BudgetHandler) GetBudgetItems(c *gin.Context) {
+	proposalID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	items, err := h.service.GetItems(proposalID, userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(budgetErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan RAB proposal",
+		"data":    items,
+	})
+}
+
+// CreateBudgetItems menambahkan baris RAB ke proposal
+func (h *BudgetHandler) CreateBudgetItems(c *gin.Context) {
+	proposalID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Items []models.BudgetItem `json:"items" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Body JSON tidak valid"})
+		return
+	}
+
+	if err := h.service.CreateItems(proposalID, body.Items, userID, isAdminRole(c)); err != nil {
+		c.JSON(budgetErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "RAB berhasil ditambahkan",
+		"data":    body.Items,
+	})
+}
+
+// UpdateBudgetItem memperbarui satu baris RAB
+func (h *BudgetHandler) UpdateBudgetItem(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	item, err := h.service.GetItemByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	var payload models.BudgetItem
+	if err := c.ShouldBindJSON(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Body JSON tidak valid"})
+		return
+	}
+	item.Category = payload.Category
+	item.Description = payload.Description
+	item.Quantity = payload.Quantity
+	item.Unit = payload.Unit
+	item.UnitPrice = payload.UnitPrice
+
+	if err := h.service.UpdateItem(item, userID, isAdminRole(c)); err != nil {
+		c.JSON(budgetErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "RAB berhasil diperbarui",
+		"data":    item,
+	})
+}
+
+// DeleteBudgetItem menghapus satu baris RAB
+func (h *BudgetHandler) DeleteBudgetItem(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteItem(id, userID, isAdminRole(c)); err != nil {
+		c.JSON(budgetErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "RAB berhasil dihapus",
+	})
+}
+
+// GetBudgetReport mengembalikan perbandingan RAB dengan realisasi pengeluaran kegiatan
+func (h *BudgetHandler) GetBudgetReport(c *gin.Context) {
+	proposalID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.GetBudgetReport(proposalID, userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(budgetErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan laporan anggaran vs realisasi",
+		"data":    report,
+	})
+}
@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"bem_be/internal/repositories"
+	"bem_be/internal/services"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// CalendarHandler menangani request feed iCalendar
+type CalendarHandler struct {
+	service *services.CalendarService
+}
+
+// NewCalendarHandler membuat handler kalender baru
+func NewCalendarHandler(db *gorm.DB) *CalendarHandler {
+	return &CalendarHandler{
+		service: services.NewCalendarService(db),
+	}
+}
+
+// writeCalendar mengirimkan feed dengan content type text/calendar
+func writeCalendar(c *gin.Context, filename, body string) {
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(body))
+}
+
+// GetPublicFeed mengembalikan feed seluruh kegiatan dan pengumuman terjadwal.
+// Query association_id, department_id, bem_id atau organization_id membatasi feed ke satu penyelenggara.
+func (h *CalendarHandler) GetPublicFeed(c *gin.Context) {
+	filter := repositories.ActivityFilter{
+		OrganizationID: parseOptionalUint(c.Query("organization_id")),
+		DepartmentID:   parseOptionalUint(c.Query("department_id")),
+		AssociationID:  parseOptionalUint(c.Query("association_id")),
+		BEMID:          parseOptionalUint(c.Query("bem_id")),
+	}
+	unfiltered := filter.OrganizationID == nil && filter.DepartmentID == nil &&
+		filter.AssociationID == nil && filter.BEMID == nil
+
+	feed, err := h.service.BuildFeed("Kalender BEM IT Del", filter, unfiltered)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	writeCalendar(c, "bem-itdel.ics", feed)
+}
+
+// GetOrganizationFeed mengembalikan feed kegiatan satu organisasi (himpunan/departemen/UKM)
+func (h *CalendarHandler) GetOrganizationFeed(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	filter := repositories.ActivityFilter{OrganizationID: &id}
+	feed, err := h.service.BuildFeed(fmt.Sprintf("Kegiatan Organisasi %d", id), filter, false)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	writeCalendar(c, fmt.Sprintf("organization-%d.ics", id), feed)
+}
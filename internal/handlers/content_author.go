@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"bem_be/internal/services"
+	"errors"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+)
+
+// resolveAuthor mengambil pengguna yang sedang login beserta organisasi yang dikelolanya
+func resolveAuthor(c *gin.Context, authors *services.AuthorService) (*services.Author, bool) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return nil, false
+	}
+	author, err := authors.ResolveAuthor(userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		return nil, false
+	}
+	return author, true
+}
+
+// authorErrorStatus memetakan error kepemilikan konten dan tag konten ke status HTTP,
+// selain itu mengembalikan fallback
+func authorErrorStatus(err error, fallback int) int {
+	switch {
+	case errors.Is(err, services.ErrNotOfficer), errors.Is(err, services.ErrContentForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrContentOwnerRequired), errors.Is(err, services.ErrUnknownTag):
+		return http.StatusBadRequest
+	default:
+		return fallback
+	}
+}
+
+// discardUpload menghapus file yang sudah diunggah jika konten gagal disimpan,
+// misalnya karena pengguna tidak berhak atas organisasi pemiliknya
+func discardUpload(path string) {
+	if path != "" {
+		_ = os.Remove(path)
+	}
+}
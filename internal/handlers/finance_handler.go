@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"bem_be/internal/services"
+	"errors"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// FinanceHandler menangani request HTTP terkait buku kas kegiatan
+type FinanceHandler struct {
+	service *services.FinanceService
+}
+
+// NewFinanceHandler membuat handler keuangan baru
+func NewFinanceHandler(db *gorm.DB) *FinanceHandler {
+	return &FinanceHandler{
+		service: services.NewFinanceService(db),
+	}
+}
+
+// bindFinanceEntryForm mengisi field transaksi dari form data
+func bindFinanceEntryForm(c *gin.Context, entry *models.FinanceEntry) error {
+	entry.Type = c.PostForm("type")
+	entry.Category = c.PostForm("category")
+	entry.Description = c.PostForm("description")
+
+	amount, err := strconv.ParseFloat(c.PostForm("amount"), 64)
+	if err != nil {
+		return errors.New("nominal transaksi tidak valid")
+	}
+	entry.Amount = amount
+
+	entryDate, err := parseDateTime(c.PostForm("entry_date"))
+	if err != nil {
+		return err
+	}
+	entry.EntryDate = entryDate
+	return nil
+}
+
+// saveReceipt menyimpan bukti transaksi opsional dan mengembalikan path-nya
+func saveReceipt(c *gin.Context) (string, error) {
+	path, err := saveFileWithExt(c, "receipt", "uploads/receipts", allowedReceiptExt, "pdf/jpg/png")
+	if err == http.ErrMissingFile {
+		return "", nil
+	}
+	return path, err
+}
+
+// GetActivityLedger mengembalikan buku kas kegiatan beserta saldo berjalan
+func (h *FinanceHandler) GetActivityLedger(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	ledger, err := h.service.GetActivityLedger(activityID, userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(financeErrorStatus(err, http.StatusNotFound), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan buku kas kegiatan",
+		"data":    ledger,
+	})
+}
+
+// CreateEntry mencatat transaksi baru untuk sebuah kegiatan
+func (h *FinanceHandler) CreateEntry(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	entry := models.FinanceEntry{
+		ActivityID:   activityID,
+		RecordedByID: userID,
+	}
+	if err := bindFinanceEntryForm(c, &entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	path, err := saveReceipt(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Gagal memproses file: " + err.Error()})
+		return
+	}
+	entry.ReceiptPath = path
+
+	if err := h.service.CreateEntry(&entry, isAdminRole(c)); err != nil {
+		if path != "" {
+			_ = os.Remove(path)
+		}
+		c.JSON(financeErrorStatus(err, http.StatusBadRequest), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Transaksi berhasil dicatat",
+		"data":    entry,
+	})
+}
+
+// financeErrorStatus mengembalikan 403 jika user bukan penyelenggara kegiatan, selain itu fallback
+func financeErrorStatus(err error, fallback int) int {
+	if errors.Is(err, services.ErrNotActivityOrganizer) {
+		return http.StatusForbidden
+	}
+	return fallback
+}
+
+// UpdateEntry memperbarui transaksi yang ada
+func (h *FinanceHandler) UpdateEntry(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	entry, err := h.service.GetEntryByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	if err := bindFinanceEntryForm(c, entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	path, err := saveReceipt(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Gagal memproses file: " + err.Error()})
+		return
+	}
+	oldPath := entry.ReceiptPath
+	if path != "" {
+		entry.ReceiptPath = path
+	}
+
+	if err := h.service.UpdateEntry(entry, userID, isAdminRole(c)); err != nil {
+		if path != "" {
+			_ = os.Remove(path)
+		}
+		c.JSON(financeErrorStatus(err, http.StatusBadRequest), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+	if path != "" && oldPath != "" {
+		_ = os.Remove(oldPath)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Transaksi berhasil diperbarui",
+		"data":    entry,
+	})
+}
+
+// DeleteEntry menghapus sebuah transaksi
+func (h *FinanceHandler) DeleteEntry(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteEntry(id, userID, isAdminRole(c)); err != nil {
+		c.JSON(financeErrorStatus(err, http.StatusNotFound), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Transaksi berhasil dihapus",
+	})
+}
+
+// GetFinanceSummary mengembalikan rekap saldo per kegiatan untuk satu organisasi atau periode
+func (h *FinanceHandler) GetFinanceSummary(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+	var filter repositories.ActivityFilter
+
+	if periodID := parseOptionalUint(c.Query("period_id")); periodID != nil {
+		var err error
+		filter, err = h.service.PeriodFilter(*periodID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+	} else {
+		filter = repositories.ActivityFilter{
+			OrganizationID: parseOptionalUint(c.Query("organization_id")),
+			DepartmentID:   parseOptionalUint(c.Query("department_id")),
+			AssociationID:  parseOptionalUint(c.Query("association_id")),
+			BEMID:          parseOptionalUint(c.Query("bem_id")),
+		}
+	}
+
+	summary, err := h.service.GetOrganizationSummary(filter, userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(financeErrorStatus(err, http.StatusInternalServerError), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan rekap keuangan",
+		"data":    summary,
+	})
+}
@@ -21,11 +21,13 @@ import (
 
 type GaleryHandler struct {
 	service *services.GaleryService
+	authors *services.AuthorService
 }
 
 func NewGaleryHandler(db *gorm.DB) *GaleryHandler {
 	return &GaleryHandler{
 		service: services.NewGaleryService(db),
+		authors: services.NewAuthorService(db),
 	}
 }
 
@@ -88,6 +90,10 @@ func saveImage(c *gin.Context, field string) (string, error) {
 }
 
 func (h *GaleryHandler) CreateGalery(c *gin.Context) {
+	author, ok := resolveAuthor(c, h.authors)
+	if !ok {
+		return
+	}
 	ct := c.ContentType()
 	var galery models.Galery
 
@@ -95,6 +101,10 @@ func (h *GaleryHandler) CreateGalery(c *gin.Context) {
 	case strings.HasPrefix(ct, "multipart/form-data"):
 		galery.Title = c.PostForm("title")
 		galery.Content = c.PostForm("content")
+		galery.Tags, _ = bindTagForm(c)
+		galery.BEMID = parseOptionalUint(c.PostForm("bem_id"))
+		galery.AssociationID = parseOptionalUint(c.PostForm("association_id"))
+		galery.DepartmentID = parseOptionalUint(c.PostForm("department_id"))
 		path, err := saveImage(c, "image_url")
 		if err != nil {
 			if err != http.ErrMissingFile {
@@ -116,8 +126,9 @@ func (h *GaleryHandler) CreateGalery(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.CreateGalery(&galery); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+	if err := h.service.CreateGalery(&galery, author); err != nil {
+		discardUpload(galery.ImageURL)
+		c.JSON(authorErrorStatus(err, http.StatusInternalServerError), gin.H{"status": "error", "message": err.Error()})
 		return
 	}
 
@@ -132,13 +143,22 @@ func (h *GaleryHandler) UpdateGalery(c *gin.Context) {
 	if !ok {
 		return
 	}
-	existing, err := h.service.GetGaleryByID(id)
+	author, ok := resolveAuthor(c, h.authors)
+	if !ok {
+		return
+	}
+	existing, err := h.service.GetManagedGaleryByID(id, author)
 	if err != nil {
+		if status := authorErrorStatus(err, http.StatusNotFound); status != http.StatusNotFound {
+			c.JSON(status, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
 		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": "Galeri tidak ditemukan"})
 		return
 	}
 
 	ct := c.ContentType()
+	oldImage, newImage := existing.ImageURL, ""
 
 	switch {
 	case strings.HasPrefix(ct, "multipart/form-data"):
@@ -148,15 +168,24 @@ func (h *GaleryHandler) UpdateGalery(c *gin.Context) {
 		if v := c.PostForm("description"); v != "" {
 			existing.Content = v
 		}
+		if tags, ok := bindTagForm(c); ok {
+			existing.Tags = tags
+		}
+		if v := parseOptionalUint(c.PostForm("bem_id")); v != nil {
+			existing.BEMID = v
+		}
+		if v := parseOptionalUint(c.PostForm("association_id")); v != nil {
+			existing.AssociationID = v
+		}
+		if v := parseOptionalUint(c.PostForm("department_id")); v != nil {
+			existing.DepartmentID = v
+		}
 
 		// File opsional
 		path, err := saveImage(c, "image")
 		if err == nil {
-			// Hapus file lama jika ada
-			if existing.ImageURL != "" {
-				_ = os.Remove(existing.ImageURL)
-			}
 			existing.ImageURL = path
+			newImage = path
 		} else if err != http.ErrMissingFile {
 			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Gagal memproses file: " + err.Error()})
 			return
@@ -174,15 +203,32 @@ func (h *GaleryHandler) UpdateGalery(c *gin.Context) {
 		if payload.Content != "" {
 			existing.Content = payload.Content
 		}
+		if payload.Tags != nil {
+			existing.Tags = payload.Tags
+		}
+		if payload.BEMID != nil {
+			existing.BEMID = payload.BEMID
+		}
+		if payload.AssociationID != nil {
+			existing.AssociationID = payload.AssociationID
+		}
+		if payload.DepartmentID != nil {
+			existing.DepartmentID = payload.DepartmentID
+		}
 	default:
 		c.JSON(http.StatusUnsupportedMediaType, gin.H{"status": "error", "message": "Gunakan application/json atau multipart/form-data"})
 		return
 	}
 
-	if err := h.service.UpdateGalery(existing); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+	if err := h.service.UpdateGalery(existing, author); err != nil {
+		discardUpload(newImage)
+		c.JSON(authorErrorStatus(err, http.StatusInternalServerError), gin.H{"status": "error", "message": err.Error()})
 		return
 	}
+	// Hapus file lama setelah galeri tersimpan dengan file baru
+	if newImage != "" {
+		discardUpload(oldImage)
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
@@ -196,10 +242,14 @@ func (h *GaleryHandler) DeleteGalery(c *gin.Context) {
 	if !ok {
 		return
 	}
+	author, ok := resolveAuthor(c, h.authors)
+	if !ok {
+		return
+	}
 	gal, _ := h.service.GetGaleryByID(id)
 
-	if err := h.service.DeleteGalery(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+	if err := h.service.DeleteGalery(id, author); err != nil {
+		c.JSON(authorErrorStatus(err, http.StatusInternalServerError), gin.H{"status": "error", "message": err.Error()})
 		return
 	}
 	if gal != nil && gal.ImageURL != "" {
@@ -212,7 +262,14 @@ func (h *GaleryHandler) DeleteGalery(c *gin.Context) {
 	})
 }
 
+// GetAllGalerys mengembalikan daftar galeri. Pengurus organisasi hanya mendapatkan galeri
+// milik organisasinya.
 func (h *GaleryHandler) GetAllGalerys(c *gin.Context) {
+	author, ok := resolveAuthor(c, h.authors)
+	if !ok {
+		return
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
 
@@ -225,9 +282,10 @@ func (h *GaleryHandler) GetAllGalerys(c *gin.Context) {
 
 	offset := (page - 1) * perPage
 
-	galerys, total, err := h.service.GetAllGalerys(perPage, offset)
+	tag := c.Query("tag")
+	galerys, total, err := h.service.GetManagedGalerys(author, tag, perPage, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, utils.ResponseHandler("error", err.Error(), nil))
+		c.JSON(authorErrorStatus(err, http.StatusInternalServerError), utils.ResponseHandler("error", err.Error(), nil))
 		return
 	}
 
@@ -239,8 +297,8 @@ func (h *GaleryHandler) GetAllGalerys(c *gin.Context) {
 		TotalItems:  int(total),
 		TotalPages:  totalPages,
 		Links: utils.PaginationLinks{
-			First: fmt.Sprintf("/galerys?page=1&per_page=%d", perPage),
-			Last:  fmt.Sprintf("/galerys?page=%d&per_page=%d", totalPages, perPage),
+			First: fmt.Sprintf("/galerys?page=1&per_page=%d%s", perPage, tagQuery(tag)),
+			Last:  fmt.Sprintf("/galerys?page=%d&per_page=%d%s", totalPages, perPage, tagQuery(tag)),
 		},
 	}
 
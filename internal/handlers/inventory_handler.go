@@ -0,0 +1,203 @@
+package handlers
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/services"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// InventoryHandler menangani request HTTP terkait inventaris barang
+type InventoryHandler struct {
+	service *services.InventoryService
+}
+
+// NewInventoryHandler membuat handler inventaris baru
+func NewInventoryHandler(db *gorm.DB) *InventoryHandler {
+	return &InventoryHandler{
+		service: services.NewInventoryService(db),
+	}
+}
+
+// bindInventoryForm mengisi field barang inventaris dari form data
+func bindInventoryForm(c *gin.Context, item *models.InventoryItem) error {
+	item.Name = c.PostForm("name")
+	item.Description = c.PostForm("description")
+	item.Condition = c.PostForm("condition")
+	item.Location = c.PostForm("location")
+
+	stock, err := strconv.ParseUint(c.DefaultPostForm("total_stock", "0"), 10, 32)
+	if err != nil {
+		return errors.New("jumlah stok tidak valid")
+	}
+	item.TotalStock = uint(stock)
+	return nil
+}
+
+// parseAvailabilityRange membaca rentang waktu ketersediaan dari query start dan end
+func parseAvailabilityRange(c *gin.Context) (start, end *time.Time, ok bool) {
+	start, err := parseOptionalDateTime(c.Query("start"))
+	if err == nil {
+		end, err = parseOptionalDateTime(c.Query("end"))
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return nil, nil, false
+	}
+	return start, end, true
+}
+
+// GetAllItems mengembalikan semua barang inventaris. Jika start dan end diisi,
+// sisa stok setiap barang pada rentang tersebut ikut dikembalikan.
+func (h *InventoryHandler) GetAllItems(c *gin.Context) {
+	start, end, ok := parseAvailabilityRange(c)
+	if !ok {
+		return
+	}
+
+	if start != nil && end != nil {
+		availabilities, err := h.service.GetAllAvailability(*start, *end)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{
+			"status":  "success",
+			"message": "Berhasil mendapatkan ketersediaan barang",
+			"data":    availabilities,
+		})
+		return
+	}
+
+	items, err := h.service.GetAllItems(c.Query("q"), c.Query("condition"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan daftar barang",
+		"data":    items,
+	})
+}
+
+// GetItemByID mengembalikan barang inventaris berdasarkan ID
+func (h *InventoryHandler) GetItemByID(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	item, err := h.service.GetItemByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Barang berhasil didapatkan",
+		"data":    item,
+	})
+}
+
+// GetItemAvailability mengembalikan sisa stok barang pada rentang waktu start sampai end
+func (h *InventoryHandler) GetItemAvailability(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	start, end, ok := parseAvailabilityRange(c)
+	if !ok {
+		return
+	}
+	if start == nil || end == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Parameter start dan end wajib diisi"})
+		return
+	}
+
+	availability, err := h.service.GetAvailability(id, *start, *end)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Ketersediaan barang berhasil didapatkan",
+		"data":    availability,
+	})
+}
+
+// CreateItem membuat barang inventaris baru
+func (h *InventoryHandler) CreateItem(c *gin.Context) {
+	var item models.InventoryItem
+	if err := bindInventoryForm(c, &item); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	if err := h.service.CreateItem(&item); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Barang berhasil dibuat",
+		"data":    item,
+	})
+}
+
+// UpdateItem memperbarui barang inventaris yang ada
+func (h *InventoryHandler) UpdateItem(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	item, err := h.service.GetItemByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+	if err := bindInventoryForm(c, item); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	if err := h.service.UpdateItem(item); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Barang berhasil diperbarui",
+		"data":    item,
+	})
+}
+
+// DeleteItem menghapus barang inventaris
+func (h *InventoryHandler) DeleteItem(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteItem(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Barang berhasil dihapus",
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/services"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// LetterHandler menangani request HTTP terkait surat peminjaman
+type LetterHandler struct {
+	service *services.LetterService
+}
+
+// NewLetterHandler membuat handler surat peminjaman baru
+func NewLetterHandler(db *gorm.DB) *LetterHandler {
+	return &LetterHandler{
+		service: services.NewLetterService(db),
+	}
+}
+
+// writeLetter mengirimkan PDF surat peminjaman
+func writeLetter(c *gin.Context, pdf []byte, letter *models.BorrowingLetter) {
+	filename := "surat-peminjaman-" + strings.NewReplacer("/", "-", " ", "").Replace(letter.Number) + ".pdf"
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%q", filename))
+	c.Data(http.StatusOK, "application/pdf", pdf)
+}
+
+// letterErrorStatus memetakan error service surat peminjaman ke HTTP status
+func letterErrorStatus(err error) int {
+	if errors.Is(err, services.ErrLetterNotAvailable) {
+		return http.StatusConflict
+	}
+	return requestErrorStatus(err)
+}
+
+// GetRequestLetter mengembalikan PDF surat peminjaman untuk permintaan yang disetujui
+func (h *LetterHandler) GetRequestLetter(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	pdf, letter, err := h.service.RenderLetter(id)
+	if err != nil {
+		c.JSON(letterErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	writeLetter(c, pdf, letter)
+}
+
+// GetMyRequestLetter mengembalikan PDF surat peminjaman milik organisasi mahasiswa yang login
+func (h *LetterHandler) GetMyRequestLetter(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	pdf, letter, err := h.service.RenderMyOrganizationLetter(id, userID)
+	if err != nil {
+		c.JSON(letterErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	writeLetter(c, pdf, letter)
+}
+
+// VerifyLetter memeriksa keaslian surat peminjaman dari kode pada QR code
+func (h *LetterHandler) VerifyLetter(c *gin.Context) {
+	verification, err := h.service.VerifyLetter(c.Param("code"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	message := "Surat peminjaman terverifikasi"
+	if !verification.Valid {
+		message = "Surat peminjaman sudah tidak berlaku"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": message,
+		"data":    verification,
+	})
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bem_be/internal/models"
+	"bem_be/internal/repositories"
 	"bem_be/internal/services"
 	"fmt"
 	"math"
@@ -9,6 +10,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -18,12 +20,14 @@ import (
 // NewsHandler menangani request HTTP terkait berita
 type NewsHandler struct {
 	service *services.NewsService
+	authors *services.AuthorService
 }
 
 // NewNewsHandler membuat handler berita baru
 func NewNewsHandler(db *gorm.DB) *NewsHandler {
 	return &NewsHandler{
 		service: services.NewNewsService(db),
+		authors: services.NewAuthorService(db),
 	}
 }
 
@@ -40,8 +44,44 @@ func parseOptionalUint(value string) *uint {
 	return &u
 }
 
-// GetAllNews mengembalikan semua berita dengan pagination
+// bindNewsSchedule mengisi status dan waktu terbit berita dari form data.
+// Field yang tidak dikirim tidak mengubah nilai yang sudah ada.
+func bindNewsSchedule(c *gin.Context, news *models.News) error {
+	if status := strings.ToLower(strings.TrimSpace(c.PostForm("status"))); status != "" {
+		news.Status = status
+	}
+	publishAt, err := parseOptionalDateTime(strings.TrimSpace(c.PostForm("publish_at")))
+	if err != nil {
+		return err
+	}
+	if publishAt != nil {
+		news.PublishAt = publishAt
+	}
+	return nil
+}
+
+// bindNewsTags membaca tag berita dari form data. Jika field tags tidak dikirim,
+// tag yang ada dipertahankan. Field category lama ditambahkan sebagai tag dan harus
+// merujuk tag yang sudah ada.
+func bindNewsTags(c *gin.Context, current []models.Tag) []models.Tag {
+	tags, ok := bindTagForm(c)
+	if !ok {
+		tags = current
+	}
+	if category := strings.TrimSpace(c.PostForm("category")); category != "" {
+		tags = append(tags, models.Tag{Name: category})
+	}
+	return tags
+}
+
+// GetAllNews mengembalikan semua berita dengan pagination, termasuk draf.
+// Pengurus organisasi hanya mendapatkan berita milik organisasinya.
 func (h *NewsHandler) GetAllNews(c *gin.Context) {
+	author, ok := resolveAuthor(c, h.authors)
+	if !ok {
+		return
+	}
+
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
 
@@ -54,9 +94,11 @@ func (h *NewsHandler) GetAllNews(c *gin.Context) {
 
 	offset := (page - 1) * perPage
 
-	newsList, total, err := h.service.GetAllNews(perPage, offset)
+	filter := newsFilterFromQuery(c)
+	filter.Status = strings.ToLower(c.Query("status"))
+	newsList, total, err := h.service.GetManagedNews(author, filter, perPage, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		c.JSON(authorErrorStatus(err, http.StatusBadRequest), gin.H{"status": "error", "message": err.Error()})
 		return
 	}
 
@@ -86,12 +128,121 @@ func (h *NewsHandler) GetNewsByID(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Format ID tidak valid"})
 		return
 	}
+	author, ok := resolveAuthor(c, h.authors)
+	if !ok {
+		return
+	}
 
-	news, err := h.service.GetNewsByID(uint(id))
+	news, err := h.service.GetManagedNewsByID(uint(id), author)
+	if err != nil {
+		c.JSON(authorErrorStatus(err, http.StatusNotFound), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berita berhasil didapatkan",
+		"data":    news,
+	})
+}
+
+// newsFilterFromQuery membaca filter tag dan pemilik berita dari query string.
+// Query category lama tetap diterima sebagai nama tag.
+func newsFilterFromQuery(c *gin.Context) repositories.NewsFilter {
+	tag := c.Query("tag")
+	if tag == "" {
+		tag = c.Query("category")
+	}
+	return repositories.NewsFilter{
+		Tag:           tag,
+		BEMID:         parseOptionalUint(c.Query("bem_id")),
+		AssociationID: parseOptionalUint(c.Query("association_id")),
+		DepartmentID:  parseOptionalUint(c.Query("department_id")),
+	}
+}
+
+// GetPublishedNews mengembalikan berita yang sudah terbit dengan pagination.
+// Query tag, bem_id, association_id dan department_id menyaring daftar berita.
+func (h *NewsHandler) GetPublishedNews(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+
+	offset := (page - 1) * perPage
+
+	newsList, total, err := h.service.GetPublishedNews(newsFilterFromQuery(c), perPage, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	var totalPages int
+	if perPage > 0 {
+		totalPages = int(math.Ceil(float64(total) / float64(perPage)))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan daftar berita",
+		"metadata": gin.H{
+			"current_page": page,
+			"per_page":     perPage,
+			"total_items":  total,
+			"total_pages":  totalPages,
+		},
+		"data": newsList,
+	})
+}
+
+// GetPublishedNewsByID mengembalikan berita yang sudah terbit berdasarkan ID
+func (h *NewsHandler) GetPublishedNewsByID(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := strconv.ParseUint(idStr, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Format ID tidak valid"})
+		return
+	}
+
+	news, err := h.service.GetPublishedNewsByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berita berhasil didapatkan",
+		"data":    news,
+	})
+}
+
+// redirectToSlug mengalihkan permintaan dengan slug lama ke slug terbaru pada rute yang sama
+func redirectToSlug(c *gin.Context, currentSlug string) {
+	path := strings.TrimSuffix(c.Request.URL.Path, c.Param("slug")) + currentSlug
+	if c.Request.URL.RawQuery != "" {
+		path += "?" + c.Request.URL.RawQuery
+	}
+	c.Redirect(http.StatusMovedPermanently, path)
+}
+
+// GetPublishedNewsBySlug mengembalikan berita yang sudah terbit berdasarkan slug.
+// Slug lama dialihkan ke slug terbaru.
+func (h *NewsHandler) GetPublishedNewsBySlug(c *gin.Context) {
+	news, err := h.service.GetPublishedNewsBySlug(c.Param("slug"))
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
 		return
 	}
+	if news.Slug != c.Param("slug") {
+		redirectToSlug(c, news.Slug)
+		return
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
@@ -100,16 +251,46 @@ func (h *NewsHandler) GetNewsByID(c *gin.Context) {
 	})
 }
 
+// GetRSSFeed mengembalikan feed RSS 2.0 berita yang sudah terbit
+func (h *NewsHandler) GetRSSFeed(c *gin.Context) {
+	feed, err := h.service.BuildRSSFeed(newsFilterFromQuery(c), c.Request.URL.RequestURI())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/rss+xml; charset=utf-8", feed)
+}
+
+// GetAtomFeed mengembalikan feed Atom berita yang sudah terbit
+func (h *NewsHandler) GetAtomFeed(c *gin.Context) {
+	feed, err := h.service.BuildAtomFeed(newsFilterFromQuery(c), c.Request.URL.RequestURI())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", feed)
+}
+
 // CreateNews membuat berita baru (dengan unggahan file opsional)
 func (h *NewsHandler) CreateNews(c *gin.Context) {
+	author, ok := resolveAuthor(c, h.authors)
+	if !ok {
+		return
+	}
 	var news models.News
 
 	news.Title = c.PostForm("title")
 	news.Content = c.PostForm("content")
-	news.Category = c.PostForm("category")
+	news.Tags = bindNewsTags(c, nil)
 	news.BEMID = parseOptionalUint(c.PostForm("bem_id"))
 	news.AssociationID = parseOptionalUint(c.PostForm("association_id"))
 	news.DepartmentID = parseOptionalUint(c.PostForm("department_id"))
+	if err := bindNewsSchedule(c, &news); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
 
 	file, err := c.FormFile("image")
 	if err == nil {
@@ -132,8 +313,9 @@ func (h *NewsHandler) CreateNews(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.CreateNews(&news); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+	if err := h.service.CreateNews(&news, author); err != nil {
+		discardUpload(news.ImageURL)
+		c.JSON(authorErrorStatus(err, http.StatusInternalServerError), gin.H{"status": "error", "message": err.Error()})
 		return
 	}
 
@@ -152,26 +334,31 @@ func (h *NewsHandler) UpdateNews(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Format ID tidak valid"})
 		return
 	}
+	author, ok := resolveAuthor(c, h.authors)
+	if !ok {
+		return
+	}
 
-	existingNews, err := h.service.GetNewsByID(uint(id))
+	existingNews, err := h.service.GetManagedNewsByID(uint(id), author)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		c.JSON(authorErrorStatus(err, http.StatusNotFound), gin.H{"status": "error", "message": err.Error()})
 		return
 	}
 
 	existingNews.Title = c.PostForm("title")
 	existingNews.Content = c.PostForm("content")
-	existingNews.Category = c.PostForm("category")
+	existingNews.Tags = bindNewsTags(c, existingNews.Tags)
 	existingNews.BEMID = parseOptionalUint(c.PostForm("bem_id"))
 	existingNews.AssociationID = parseOptionalUint(c.PostForm("association_id"))
 	existingNews.DepartmentID = parseOptionalUint(c.PostForm("department_id"))
+	if err := bindNewsSchedule(c, existingNews); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
 
+	oldImage, newImage := existingNews.ImageURL, ""
 	file, err := c.FormFile("image")
 	if err == nil {
-		if existingNews.ImageURL != "" {
-			_ = os.Remove(existingNews.ImageURL)
-		}
-
 		uploadPath := "uploads/news"
 		_ = os.MkdirAll(uploadPath, os.ModePerm)
 
@@ -183,12 +370,18 @@ func (h *NewsHandler) UpdateNews(c *gin.Context) {
 			return
 		}
 		existingNews.ImageURL = filePath
+		newImage = filePath
 	}
 
-	if err := h.service.UpdateNews(existingNews); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+	if err := h.service.UpdateNews(existingNews, author); err != nil {
+		discardUpload(newImage)
+		c.JSON(authorErrorStatus(err, http.StatusInternalServerError), gin.H{"status": "error", "message": err.Error()})
 		return
 	}
+	// Hapus gambar lama setelah berita tersimpan dengan gambar baru
+	if newImage != "" {
+		discardUpload(oldImage)
+	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "success",
@@ -205,9 +398,13 @@ func (h *NewsHandler) DeleteNews(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Format ID tidak valid"})
 		return
 	}
+	author, ok := resolveAuthor(c, h.authors)
+	if !ok {
+		return
+	}
 
-	if err := h.service.DeleteNews(uint(id)); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+	if err := h.service.DeleteNews(uint(id), author); err != nil {
+		c.JSON(authorErrorStatus(err, http.StatusInternalServerError), gin.H{"status": "error", "message": err.Error()})
 		return
 	}
 
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"bem_be/internal/repositories"
+	"bem_be/internal/services"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Rentang bawaan dan maksimum untuk daftar pertemuan kegiatan
+const (
+	defaultOccurrenceRangeDays = 30
+	maxOccurrenceRangeDays     = 366
+)
+
+// OccurrenceHandler menangani request HTTP terkait pertemuan kegiatan berulang
+type OccurrenceHandler struct {
+	service *services.OccurrenceService
+}
+
+// NewOccurrenceHandler membuat handler pertemuan kegiatan baru
+func NewOccurrenceHandler(db *gorm.DB) *OccurrenceHandler {
+	return &OccurrenceHandler{
+		service: services.NewOccurrenceService(db),
+	}
+}
+
+// parseOccurrenceRange membaca query from/to; bawaannya 30 hari sejak hari ini
+func parseOccurrenceRange(c *gin.Context) (*time.Time, *time.Time, bool) {
+	from, err := parseOptionalDateTime(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return nil, nil, false
+	}
+	to, err := parseOptionalDateTime(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return nil, nil, false
+	}
+
+	if from == nil {
+		now := time.Now()
+		today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+		from = &today
+	}
+	if to == nil {
+		end := from.AddDate(0, 0, defaultOccurrenceRangeDays)
+		to = &end
+	}
+	if to.Before(*from) || to.Sub(*from) > maxOccurrenceRangeDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Rentang tanggal tidak valid (maksimal 366 hari)"})
+		return nil, nil, false
+	}
+	return from, to, true
+}
+
+// parseOccurrenceStart membaca penanda pertemuan (waktu mulai menurut aturan pengulangan)
+func parseOccurrenceStart(c *gin.Context) (time.Time, bool) {
+	value := c.PostForm("occurrence_start")
+	if value == "" {
+		value = c.Query("occurrence_start")
+	}
+	if value == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "occurrence_start wajib diisi"})
+		return time.Time{}, false
+	}
+	start, err := parseDateTime(value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return time.Time{}, false
+	}
+	return start, true
+}
+
+// respondOccurrenceError memetakan error service pertemuan ke HTTP status
+func respondOccurrenceError(c *gin.Context, err error, conflicts []services.ActivityConflict) {
+	switch {
+	case errors.Is(err, services.ErrVenueConflict):
+		c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error(), "conflicts": conflicts})
+	case errors.Is(err, services.ErrNotRecurring):
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+	case errors.Is(err, services.ErrOccurrenceNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+	}
+}
+
+// GetOccurrences mengembalikan semua pertemuan kegiatan (kegiatan berulang sudah dijabarkan)
+func (h *OccurrenceHandler) GetOccurrences(c *gin.Context) {
+	from, to, ok := parseOccurrenceRange(c)
+	if !ok {
+		return
+	}
+
+	filter := repositories.ActivityFilter{
+		DepartmentID:  parseOptionalUint(c.Query("department_id")),
+		AssociationID: parseOptionalUint(c.Query("association_id")),
+		BEMID:         parseOptionalUint(c.Query("bem_id")),
+		From:          from,
+		To:            to,
+	}
+
+	occurrences, err := h.service.GetOccurrences(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan jadwal kegiatan",
+		"data":    occurrences,
+	})
+}
+
+// GetActivityOccurrences mengembalikan pertemuan sebuah kegiatan
+func (h *OccurrenceHandler) GetActivityOccurrences(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	from, err := parseOptionalDateTime(c.Query("from"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+	to, err := parseOptionalDateTime(c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	occurrences, err := h.service.GetActivityOccurrences(activityID, from, to)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan pertemuan kegiatan",
+		"data":    occurrences,
+	})
+}
+
+// UpdateOccurrence mengubah satu pertemuan kegiatan berulang
+func (h *OccurrenceHandler) UpdateOccurrence(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	occurrenceStart, ok := parseOccurrenceStart(c)
+	if !ok {
+		return
+	}
+
+	startDate, err := parseOptionalDateTime(c.PostForm("start_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+	endDate, err := parseOptionalDateTime(c.PostForm("end_date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	input := services.OccurrenceInput{
+		Title:       c.PostForm("title"),
+		Description: c.PostForm("description"),
+		Location:    c.PostForm("location"),
+		StartDate:   startDate,
+		EndDate:     endDate,
+	}
+
+	occurrence, conflicts, err := h.service.UpdateOccurrence(activityID, occurrenceStart, input)
+	if err != nil {
+		respondOccurrenceError(c, err, conflicts)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "success",
+		"message":   activityConflictMessage("Pertemuan berhasil diperbarui", conflicts),
+		"data":      occurrence,
+		"conflicts": conflicts,
+	})
+}
+
+// CancelOccurrence membatalkan satu pertemuan kegiatan berulang
+func (h *OccurrenceHandler) CancelOccurrence(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	occurrenceStart, ok := parseOccurrenceStart(c)
+	if !ok {
+		return
+	}
+
+	occurrence, err := h.service.CancelOccurrence(activityID, occurrenceStart)
+	if err != nil {
+		respondOccurrenceError(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Pertemuan berhasil dibatalkan",
+		"data":    occurrence,
+	})
+}
+
+// RestoreOccurrence mengembalikan pertemuan agar kembali mengikuti rangkaian kegiatannya
+func (h *OccurrenceHandler) RestoreOccurrence(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	occurrenceStart, ok := parseOccurrenceStart(c)
+	if !ok {
+		return
+	}
+
+	occurrence, err := h.service.RestoreOccurrence(activityID, occurrenceStart)
+	if err != nil {
+		respondOccurrenceError(c, err, nil)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Pertemuan berhasil dikembalikan sesuai rangkaian",
+		"data":    occurrence,
+	})
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/services"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ProposalHandler menangani request HTTP terkait proposal kegiatan
+type ProposalHandler struct {
+	service *services.ProposalService
+}
+
+// NewProposalHandler membuat handler proposal baru
+func NewProposalHandler(db *gorm.DB) *ProposalHandler {
+	return &ProposalHandler{
+		service: services.NewProposalService(db),
+	}
+}
+
+const maxDocumentSize = 10 << 20
+
+// Ekstensi dokumen yang boleh diunggah untuk proposal dan laporan
+var allowedDocumentExt = map[string]bool{
+	".pdf":  true,
+	".doc":  true,
+	".docx": true,
+}
+
+// Ekstensi bukti transaksi (kuitansi/nota) yang boleh diunggah
+var allowedReceiptExt = map[string]bool{
+	".pdf":  true,
+	".jpg":  true,
+	".jpeg": true,
+	".png":  true,
+}
+
+// Helper untuk mengambil ID user dari token yang sudah divalidasi middleware
+func currentUserID(c *gin.Context) (uint, bool) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "message": "Unauthorized"})
+		return 0, false
+	}
+	id, ok := userID.(uint)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"status": "error", "message": "Unauthorized"})
+		return 0, false
+	}
+	return id, true
+}
+
+// Helper untuk memeriksa apakah pemanggil memiliki role Admin
+func isAdminRole(c *gin.Context) bool {
+	role, _ := c.Get("role")
+	roleStr, _ := role.(string)
+	return strings.EqualFold(roleStr, "Admin")
+}
+
+// saveDocument menyimpan dokumen (pdf/doc/docx) yang diunggah ke folder tujuan
+func saveDocument(c *gin.Context, field, dir string) (string, error) {
+	return saveFileWithExt(c, field, dir, allowedDocumentExt, "pdf/doc/docx")
+}
+
+// saveFileWithExt menyimpan file yang diunggah jika ekstensinya termasuk yang diizinkan
+func saveFileWithExt(c *gin.Context, field, dir string, allowed map[string]bool, allowedLabel string) (string, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxDocumentSize)
+
+	file, err := c.FormFile(field)
+	if err != nil {
+		return "", err
+	}
+
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if !allowed[ext] {
+		return "", fmt.Errorf("tipe file tidak didukung (hanya %s)", allowedLabel)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("gagal membuat folder upload")
+	}
+
+	name := fmt.Sprintf("%d_%s", time.Now().UnixNano(), filepath.Base(file.Filename))
+	path := filepath.Join(dir, name)
+
+	if err := c.SaveUploadedFile(file, path); err != nil {
+		return "", fmt.Errorf("gagal menyimpan file")
+	}
+	return path, nil
+}
+
+// proposalErrorStatus memetakan error saat membaca proposal ke status HTTP
+func proposalErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrProposalNotFound), errors.Is(err, services.ErrActivityNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrNotActivityOrganizer):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// SubmitProposal mengunggah proposal untuk sebuah kegiatan
+func (h *ProposalHandler) SubmitProposal(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	path, err := saveDocument(c, "file", "uploads/proposals")
+	if err != nil {
+		if err == http.ErrMissingFile {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "File proposal wajib diunggah"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Gagal memproses file: " + err.Error()})
+		return
+	}
+
+	proposal := models.Proposal{
+		ActivityID:    activityID,
+		FilePath:      path,
+		SubmittedByID: userID,
+	}
+	if err := h.service.SubmitProposal(&proposal, isAdminRole(c)); err != nil {
+		_ = os.Remove(path)
+		if errors.Is(err, services.ErrOutstandingReports) || errors.Is(err, services.ErrNotActivityOrganizer) {
+			c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": err.Error()})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Proposal berhasil diajukan",
+		"data":    proposal,
+	})
+}
+
+// GetProposalsByActivity mengembalikan semua proposal milik sebuah kegiatan
+func (h *ProposalHandler) GetProposalsByActivity(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	proposals, err := h.service.GetProposalsByActivity(activityID, userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(proposalErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan daftar proposal",
+		"data":    proposals,
+	})
+}
+
+// GetProposalByID mengembalikan proposal beserta riwayat tinjauannya
+func (h *ProposalHandler) GetProposalByID(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	proposal, err := h.service.GetReadableProposal(id, userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(proposalErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Proposal berhasil didapatkan",
+		"data":    proposal,
+	})
+}
+
+// DownloadProposal mengirimkan file proposal
+func (h *ProposalHandler) DownloadProposal(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	proposal, err := h.service.GetReadableProposal(id, userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(proposalErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.FileAttachment(proposal.FilePath, filepath.Base(proposal.FilePath))
+}
+
+// ReviewProposal memindahkan proposal ke status berikutnya dengan komentar peninjau
+func (h *ProposalHandler) ReviewProposal(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var body struct {
+		Status  string `json:"status" form:"status" binding:"required"`
+		Comment string `json:"comment" form:"comment"`
+	}
+	if err := c.ShouldBind(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Status tujuan wajib diisi"})
+		return
+	}
+
+	proposal, err := h.service.ReviewProposal(id, body.Status, body.Comment, userID, isAdminRole(c))
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidProposalTransition):
+			c.JSON(http.StatusConflict, gin.H{"status": "error", "message": err.Error()})
+		case errors.Is(err, services.ErrProposalReviewForbidden):
+			c.JSON(http.StatusForbidden, gin.H{"status": "error", "message": err.Error()})
+		default:
+			c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Status proposal berhasil diperbarui",
+		"data":    proposal,
+	})
+}
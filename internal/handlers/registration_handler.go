@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/services"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// RegistrationHandler menangani request HTTP terkait pendaftaran kegiatan
+type RegistrationHandler struct {
+	service *services.RegistrationService
+}
+
+// NewRegistrationHandler membuat handler pendaftaran kegiatan baru
+func NewRegistrationHandler(db *gorm.DB) *RegistrationHandler {
+	return &RegistrationHandler{
+		service: services.NewRegistrationService(db),
+	}
+}
+
+// RegisterActivity mendaftarkan mahasiswa yang login ke sebuah kegiatan
+func (h *RegistrationHandler) RegisterActivity(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	registration, err := h.service.Register(activityID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	message := "Pendaftaran kegiatan berhasil"
+	if registration.Status == models.RegistrationWaitlisted {
+		message = "Kuota kegiatan penuh, anda masuk daftar tunggu"
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": message,
+		"data":    registration,
+	})
+}
+
+// CancelRegistration membatalkan pendaftaran mahasiswa yang login pada sebuah kegiatan
+func (h *RegistrationHandler) CancelRegistration(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	registration, err := h.service.Cancel(activityID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Pendaftaran kegiatan berhasil dibatalkan",
+		"data":    registration,
+	})
+}
+
+// GetMyRegistrations mengembalikan semua pendaftaran milik mahasiswa yang login
+func (h *RegistrationHandler) GetMyRegistrations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	registrations, err := h.service.GetMyRegistrations(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan daftar pendaftaran",
+		"data":    registrations,
+	})
+}
+
+// registrantsErrorStatus memetakan error akses daftar pendaftar ke status HTTP
+func registrantsErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrNotActivityOrganizer):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrActivityNotFound):
+		return http.StatusNotFound
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// GetRegistrants mengembalikan daftar pendaftar kegiatan, dapat difilter dengan query status
+func (h *RegistrationHandler) GetRegistrants(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	registrations, err := h.service.GetRegistrants(activityID, c.Query("status"), userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(registrantsErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan daftar pendaftar kegiatan",
+		"data":    registrations,
+	})
+}
+
+// ExportRegistrants mengunduh daftar pendaftar kegiatan dalam format CSV
+func (h *RegistrationHandler) ExportRegistrants(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	registrations, err := h.service.GetRegistrants(activityID, c.Query("status"), userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(registrantsErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=pendaftar-kegiatan-%d.csv", activityID))
+
+	writer := csv.NewWriter(c.Writer)
+	_ = writer.Write([]string{"No", "NIM", "Nama", "Program Studi", "Angkatan", "Email", "Status", "Waktu Daftar"})
+	for i, registration := range registrations {
+		row := []string{strconv.Itoa(i + 1), "", "", "", "", "", registration.Status, registration.RegisteredAt.Format("2006-01-02 15:04:05")}
+		if student := registration.Student; student != nil {
+			row[1] = student.NIM
+			row[2] = student.FullName
+			row[3] = student.StudyProgram
+			row[4] = strconv.Itoa(student.YearEnrolled)
+			row[5] = student.Email
+		}
+		_ = writer.Write(row)
+	}
+	writer.Flush()
+}
@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"bem_be/internal/repositories"
+	"bem_be/internal/services"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// ReportHandler menangani request HTTP terkait LPJ kegiatan
+type ReportHandler struct {
+	service *services.ReportService
+}
+
+// NewReportHandler membuat handler LPJ baru
+func NewReportHandler(db *gorm.DB) *ReportHandler {
+	return &ReportHandler{
+		service: services.NewReportService(db),
+	}
+}
+
+// reportErrorStatus memetakan error saat membaca LPJ ke status HTTP
+func reportErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrReportNotFound), errors.Is(err, services.ErrActivityNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrNotActivityOrganizer):
+		return http.StatusForbidden
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// SubmitReport mengunggah LPJ untuk sebuah kegiatan
+func (h *ReportHandler) SubmitReport(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	path, err := saveDocument(c, "file", "uploads/reports")
+	if err != nil {
+		if err == http.ErrMissingFile {
+			c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "File LPJ wajib diunggah"})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Gagal memproses file: " + err.Error()})
+		return
+	}
+
+	report, err := h.service.SubmitReport(activityID, path, userID, isAdminRole(c))
+	if err != nil {
+		_ = os.Remove(path)
+		status := http.StatusBadRequest
+		if errors.Is(err, services.ErrNotActivityOrganizer) {
+			status = http.StatusForbidden
+		}
+		c.JSON(status, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "LPJ berhasil dikumpulkan",
+		"data":    report,
+	})
+}
+
+// GetReportByActivity mengembalikan LPJ milik sebuah kegiatan
+func (h *ReportHandler) GetReportByActivity(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.GetReportByActivity(activityID, userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(reportErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "LPJ berhasil didapatkan",
+		"data":    report,
+	})
+}
+
+// DownloadReport mengirimkan file LPJ sebuah kegiatan
+func (h *ReportHandler) DownloadReport(c *gin.Context) {
+	activityID, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	report, err := h.service.GetReportByActivity(activityID, userID, isAdminRole(c))
+	if err != nil {
+		c.JSON(reportErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.FileAttachment(report.FilePath, filepath.Base(report.FilePath))
+}
+
+// GetOutstandingReports mengembalikan daftar LPJ terlambat per organisasi
+func (h *ReportHandler) GetOutstandingReports(c *gin.Context) {
+	filter := repositories.ActivityFilter{
+		DepartmentID:  parseOptionalUint(c.Query("department_id")),
+		AssociationID: parseOptionalUint(c.Query("association_id")),
+		BEMID:         parseOptionalUint(c.Query("bem_id")),
+	}
+
+	outstanding, err := h.service.GetOutstandingReports(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan daftar LPJ yang terlambat",
+		"data":    outstanding,
+	})
+}
@@ -4,8 +4,8 @@ import (
 	"bem_be/internal/models"
 	"bem_be/internal/services"
 	"bem_be/internal/utils"
+	"errors"
 	"fmt"
-	"log"
 	"math"
 	"net/http"
 	"strconv"
@@ -125,43 +125,53 @@ func (h *RequestHandler) GetRequestByID(c *gin.Context) {
 // 	c.JSON(http.StatusCreated, gin.H{"message": "Request created successfully", "data": request})
 // }
 
-func (h *RequestHandler) CreateRequest(c *gin.Context) {
-	var request models.Request
+// bindRequestForm mengisi field permintaan peminjaman dari form data.
+// Barang yang sudah dipilih tetap dipakai jika item_id tidak dikirim.
+func bindRequestForm(c *gin.Context, request *models.Request) error {
 	request.Name = c.PostForm("name")
 	quantityStr := c.DefaultPostForm("quantity", "1")
 	quantity, err := strconv.ParseUint(quantityStr, 10, 32)
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quantity"})
-		return
+		return errors.New("Invalid quantity")
 	}
 	request.Quantity = uint(quantity)
+	if itemID := parseOptionalUint(c.PostForm("item_id")); itemID != nil {
+		request.ItemID = itemID
+	}
 	request.RequestPlan = c.PostForm("request_plan")
 	request.ReturnPlan = c.PostForm("return_plan")
+	return nil
+}
+
+func (h *RequestHandler) CreateRequest(c *gin.Context) {
+	var request models.Request
+	if err := bindRequestForm(c, &request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	userID, exists := c.Get("userID")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
 		return
 	}
 	request.RequesterID = int(userID.(uint))
-	var student *models.Student
-	student, err = h.service.GetStudentByUserID(request.RequesterID)
-	if err != nil {
+	student, err := h.service.GetStudentByUserID(request.RequesterID)
+	if err != nil || student == nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
 		return
 	}
-	log.Printf("Student OrganizationID: %d, OrganizationName: %s", student.OrganizationID, student.Organization.Name)
-
 	if student.Organization == nil || student.OrganizationID == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Student is not assigned to any organization"})
 		return
 	}
+
 	request.OrganizationID = student.OrganizationID
 	request.OrganizationName = student.Organization.Name
-	request.Status = "Pending"
+	request.Status = models.RequestStatusPending
 	request.CreatedAt = time.Now()
 	request.UpdatedAt = time.Now()
 	if err := h.service.CreateRequest(&request); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(requestErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
@@ -184,23 +194,17 @@ func (h *RequestHandler) UpdateRequest(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Request not found"})
 		return
 	}
-	if request.Status == "Approved" {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Cannot update request after approval"})
+	if request.Status != models.RequestStatusPending {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Cannot update request after it has been processed"})
 		return
 	}
-	request.Name = c.PostForm("name")
-	quantityStr := c.DefaultPostForm("quantity", "1")
-	quantity, err := strconv.ParseUint(quantityStr, 10, 32)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid quantity"})
+	if err := bindRequestForm(c, request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	request.Quantity = uint(quantity)
-	request.RequestPlan = c.PostForm("request_plan")
-	request.ReturnPlan = c.PostForm("return_plan")
 	request.UpdatedAt = time.Now()
 	if err := h.service.UpdateRequest(request); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(requestErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{
@@ -223,11 +227,15 @@ func (h *RequestHandler) DeleteRequest(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Request not found"})
 		return
 	}
-	if request.Status == "Approved" {
+	if request.Status == models.RequestStatusApproved {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Cannot delete request after approval"})
 		return
 	}
 	if err := h.service.DeleteRequest(uint(id)); err != nil {
+		if errors.Is(err, services.ErrRequestNotEditable) {
+			c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -236,3 +244,229 @@ func (h *RequestHandler) DeleteRequest(c *gin.Context) {
 		"message": "Request deleted successfully",
 	})
 }
+
+// requestErrorStatus memetakan error service permintaan peminjaman ke HTTP status
+func requestErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrRequestNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, services.ErrRequestNotEditable), errors.Is(err, services.ErrRequestForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, services.ErrInvalidRequestTransition),
+		errors.Is(err, services.ErrInsufficientStock),
+		errors.Is(err, services.ErrItemUnavailable):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// changeRequestStatus menjalankan aksi perubahan status permintaan peminjaman dengan alasan dari body
+func (h *RequestHandler) changeRequestStatus(c *gin.Context, message string, action func(id, userID uint, reason string) (*models.Request, error)) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Reason string `json:"reason" form:"reason"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	request, err := action(id, userID, input.Reason)
+	if err != nil {
+		c.JSON(requestErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": message,
+		"data":    request,
+	})
+}
+
+// HandOverRequest mencatat penyerahan barang kepada peminjam
+func (h *RequestHandler) HandOverRequest(c *gin.Context) {
+	h.changeRequestStatus(c, "Penyerahan barang berhasil dicatat", h.service.HandOverRequest)
+}
+
+// ReturnRequest mencatat pengembalian barang beserta catatan kondisinya
+func (h *RequestHandler) ReturnRequest(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		Condition string `json:"condition" form:"condition" binding:"required"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Catatan kondisi barang wajib diisi"})
+		return
+	}
+
+	request, err := h.service.ReturnRequest(id, userID, input.Condition)
+	if err != nil {
+		c.JSON(requestErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Pengembalian barang berhasil dicatat",
+		"data":    request,
+	})
+}
+
+// GetOverdueRequests mengembalikan peminjaman yang barangnya belum dikembalikan melewati rencana kembali
+func (h *RequestHandler) GetOverdueRequests(c *gin.Context) {
+	requests, err := h.service.GetOverdueRequests()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan daftar peminjaman yang terlambat dikembalikan",
+		"data":    requests,
+	})
+}
+
+// ApproveRequest menyetujui permintaan peminjaman
+func (h *RequestHandler) ApproveRequest(c *gin.Context) {
+	h.changeRequestStatus(c, "Permintaan peminjaman berhasil disetujui", h.service.ApproveRequest)
+}
+
+// RejectRequest menolak permintaan peminjaman
+func (h *RequestHandler) RejectRequest(c *gin.Context) {
+	h.changeRequestStatus(c, "Permintaan peminjaman berhasil ditolak", h.service.RejectRequest)
+}
+
+// CancelRequest membatalkan permintaan peminjaman
+func (h *RequestHandler) CancelRequest(c *gin.Context) {
+	h.changeRequestStatus(c, "Permintaan peminjaman berhasil dibatalkan", h.service.CancelRequest)
+}
+
+// GetMyRequests mengembalikan permintaan peminjaman milik organisasi mahasiswa yang login
+func (h *RequestHandler) GetMyRequests(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+
+	offset := (page - 1) * perPage
+
+	requests, total, err := h.service.GetMyOrganizationRequests(userID, c.Query("status"), perPage, offset)
+	if err != nil {
+		c.JSON(http.StatusNotFound, utils.ResponseHandler("error", err.Error(), nil))
+		return
+	}
+
+	totalPages := int(math.Ceil(float64(total) / float64(perPage)))
+
+	metadata := utils.PaginationMetadata{
+		CurrentPage: page,
+		PerPage:     perPage,
+		TotalItems:  int(total),
+		TotalPages:  totalPages,
+		Links: utils.PaginationLinks{
+			First: fmt.Sprintf("/request?page=1&per_page=%d", perPage),
+			Last:  fmt.Sprintf("/request?page=%d&per_page=%d", totalPages, perPage),
+		},
+	}
+
+	response := utils.MetadataFormatResponse(
+		"success",
+		"Berhasil mendapatkan daftar permintaan peminjaman organisasi anda",
+		metadata,
+		requests,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetMyRequestByID mengembalikan permintaan peminjaman milik organisasi mahasiswa yang login
+func (h *RequestHandler) GetMyRequestByID(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.service.GetMyOrganizationRequest(id, userID)
+	if err != nil {
+		c.JSON(requestErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Permintaan peminjaman berhasil didapatkan",
+		"data":    request,
+	})
+}
+
+// UpdateMyRequest memperbarui permintaan peminjaman milik organisasi mahasiswa yang login
+func (h *RequestHandler) UpdateMyRequest(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.service.GetMyOrganizationRequest(id, userID)
+	if err != nil {
+		c.JSON(requestErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+	if err := bindRequestForm(c, request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+	request.UpdatedAt = time.Now()
+
+	if err := h.service.UpdateMyOrganizationRequest(request, userID); err != nil {
+		c.JSON(requestErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Permintaan peminjaman berhasil diperbarui",
+		"data":    request,
+	})
+}
+
+// CancelMyRequest membatalkan permintaan peminjaman milik organisasi mahasiswa yang login
+func (h *RequestHandler) CancelMyRequest(c *gin.Context) {
+	h.changeRequestStatus(c, "Permintaan peminjaman berhasil dibatalkan", h.service.CancelMyOrganizationRequest)
+}
@@ -0,0 +1,91 @@
+package handlers
+
+import (
+	"bem_be/internal/search"
+	"bem_be/internal/services"
+	"bem_be/internal/utils"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// SearchHandler menangani request pencarian gabungan
+type SearchHandler struct {
+	service *services.SearchService
+}
+
+// NewSearchHandler membuat handler pencarian baru
+func NewSearchHandler(db *gorm.DB) *SearchHandler {
+	return &SearchHandler{
+		service: services.NewSearchService(db),
+	}
+}
+
+// Search mencari berita, pengumuman, galeri, organisasi, dan kegiatan sekaligus.
+// Query q berisi kata kunci, type (dipisah koma) membatasi jenis konten.
+func (h *SearchHandler) Search(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = 10
+	}
+
+	offset := (page - 1) * perPage
+
+	var types []string
+	if typeStr := c.Query("type"); typeStr != "" {
+		types = strings.Split(typeStr, ",")
+	}
+
+	result, err := h.service.Search(c.Query("q"), types, perPage, offset)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, services.ErrSearchQueryTooShort) || errors.Is(err, services.ErrInvalidSearchType) {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, utils.ResponseHandler("error", err.Error(), nil))
+		return
+	}
+
+	// Total is the real number of matches, but only the top search.MaxResults can be paged through
+	totalPages := int(math.Ceil(float64(result.Pageable()) / float64(perPage)))
+
+	query := url.Values{"q": {c.Query("q")}}
+	if len(types) > 0 {
+		query.Set("type", c.Query("type"))
+	}
+	metadata := utils.PaginationMetadata{
+		CurrentPage: page,
+		PerPage:     perPage,
+		TotalItems:  result.Total,
+		TotalPages:  totalPages,
+		Links: utils.PaginationLinks{
+			First: fmt.Sprintf("/search?%s&page=1&per_page=%d", query.Encode(), perPage),
+			Last:  fmt.Sprintf("/search?%s&page=%d&per_page=%d", query.Encode(), totalPages, perPage),
+		},
+	}
+
+	message := "Berhasil mendapatkan hasil pencarian"
+	if result.Capped {
+		message = fmt.Sprintf("%s, menampilkan %d hasil teratas dari %d", message, search.MaxResults, result.Total)
+	}
+	response := utils.MetadataFormatResponse(
+		"success",
+		message,
+		metadata,
+		result.Hits,
+	)
+
+	c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/services"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TagHandler menangani request HTTP terkait tag
+type TagHandler struct {
+	service *services.TagService
+}
+
+// NewTagHandler membuat handler tag baru
+func NewTagHandler(db *gorm.DB) *TagHandler {
+	return &TagHandler{
+		service: services.NewTagService(db),
+	}
+}
+
+// bindTagForm membaca tag dari field tags pada form data. Setiap nilai adalah ID, slug,
+// atau nama tag yang sudah ada. Field dapat dikirim berulang atau sebagai daftar yang
+// dipisah koma. Nilai kedua bernilai false jika field tags tidak dikirim sama sekali.
+func bindTagForm(c *gin.Context) ([]models.Tag, bool) {
+	values, ok := c.GetPostFormArray("tags")
+	if !ok {
+		return nil, false
+	}
+	tags := []models.Tag{}
+	for _, value := range values {
+		for _, name := range strings.Split(value, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				tags = append(tags, models.Tag{Name: name})
+			}
+		}
+	}
+	return tags, true
+}
+
+// tagQuery membuat parameter query tag untuk link pagination
+func tagQuery(tag string) string {
+	if tag == "" {
+		return ""
+	}
+	return "&tag=" + url.QueryEscape(tag)
+}
+
+// tagErrorStatus memetakan error service tag ke status HTTP
+func tagErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrTagExists):
+		return http.StatusConflict
+	case errors.Is(err, services.ErrInvalidTagMerge):
+		return http.StatusBadRequest
+	case strings.Contains(err.Error(), "tidak ditemukan"):
+		return http.StatusNotFound
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// GetAllTags mengembalikan semua tag beserta jumlah pemakaiannya
+func (h *TagHandler) GetAllTags(c *gin.Context) {
+	tags, err := h.service.GetAllTags(c.Query("q"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan daftar tag",
+		"data":    tags,
+	})
+}
+
+// CreateTag membuat tag baru
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var payload struct {
+		Name string `json:"name" form:"name" binding:"required"`
+	}
+	if err := c.ShouldBind(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Nama tag wajib diisi"})
+		return
+	}
+
+	tag, err := h.service.CreateTag(payload.Name)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Tag berhasil dibuat",
+		"data":    tag,
+	})
+}
+
+// RenameTag mengganti nama tag
+func (h *TagHandler) RenameTag(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var payload struct {
+		Name string `json:"name" form:"name" binding:"required"`
+	}
+	if err := c.ShouldBind(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "Nama tag wajib diisi"})
+		return
+	}
+
+	tag, err := h.service.RenameTag(id, payload.Name)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Tag berhasil diperbarui",
+		"data":    tag,
+	})
+}
+
+// DeleteTag menghapus tag dan melepasnya dari semua konten
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteTag(id); err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Tag berhasil dihapus",
+	})
+}
+
+// MergeTag menggabungkan tag ke tag target
+func (h *TagHandler) MergeTag(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+	var payload struct {
+		TargetID uint `json:"target_id" form:"target_id" binding:"required"`
+	}
+	if err := c.ShouldBind(&payload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": "target_id wajib diisi"})
+		return
+	}
+
+	tag, err := h.service.MergeTags(id, payload.TargetID)
+	if err != nil {
+		c.JSON(tagErrorStatus(err), gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Tag berhasil digabungkan",
+		"data":    tag,
+	})
+}
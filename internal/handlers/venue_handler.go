@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/services"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// VenueHandler menangani request HTTP terkait lokasi kegiatan
+type VenueHandler struct {
+	service *services.VenueService
+}
+
+// NewVenueHandler membuat handler lokasi baru
+func NewVenueHandler(db *gorm.DB) *VenueHandler {
+	return &VenueHandler{
+		service: services.NewVenueService(db),
+	}
+}
+
+// bindVenueForm mengisi field lokasi dari form data
+func bindVenueForm(c *gin.Context, venue *models.Venue) {
+	venue.Name = c.PostForm("name")
+	venue.Description = c.PostForm("description")
+	venue.Exclusive, _ = strconv.ParseBool(c.DefaultPostForm("exclusive", "false"))
+}
+
+// GetAllVenues mengembalikan semua lokasi kegiatan
+func (h *VenueHandler) GetAllVenues(c *gin.Context) {
+	venues, err := h.service.GetAllVenues()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Berhasil mendapatkan daftar lokasi",
+		"data":    venues,
+	})
+}
+
+// CreateVenue membuat lokasi baru
+func (h *VenueHandler) CreateVenue(c *gin.Context) {
+	var venue models.Venue
+	bindVenueForm(c, &venue)
+
+	if err := h.service.CreateVenue(&venue); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"status":  "success",
+		"message": "Lokasi berhasil dibuat",
+		"data":    venue,
+	})
+}
+
+// UpdateVenue memperbarui lokasi yang ada
+func (h *VenueHandler) UpdateVenue(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	venue, err := h.service.GetVenueByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+	bindVenueForm(c, venue)
+
+	if err := h.service.UpdateVenue(venue); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Lokasi berhasil diperbarui",
+		"data":    venue,
+	})
+}
+
+// DeleteVenue menghapus sebuah lokasi
+func (h *VenueHandler) DeleteVenue(c *gin.Context) {
+	id, ok := parseIDParam(c, "id")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteVenue(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"status": "error", "message": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status":  "success",
+		"message": "Lokasi berhasil dihapus",
+	})
+}
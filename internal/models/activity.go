@@ -7,50 +7,136 @@ import (
 )
 
 // Activity represents a general event that can be organized by any entity.
+// A non-empty RecurrenceFrequency turns it into a series whose first occurrence
+// is StartDate..EndDate; the series is bounded by RecurrenceUntil or RecurrenceCount.
 type Activity struct {
-	ID            uint           `json:"id" gorm:"primaryKey"`
-	DepartmentID  *uint          `json:"department_id,omitempty" gorm:"index"`
-	AssociationID *uint          `json:"association_id,omitempty" gorm:"index"`
-	BEMID         *uint          `json:"bem_id,omitempty" gorm:"index"`
-	Title         string         `json:"title" gorm:"type:varchar(255);not null"`
-	Description   string         `json:"description" gorm:"type:text"`
-	StartDate     time.Time      `json:"start_date"`
-	EndDate       time.Time      `json:"end_date"`
-	Location      string         `json:"location" gorm:"type:varchar(255)"`
-	CreatedAt     time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt     time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                  uint           `json:"id" gorm:"primaryKey"`
+	DepartmentID        *uint          `json:"department_id,omitempty" gorm:"index"`
+	AssociationID       *uint          `json:"association_id,omitempty" gorm:"index"`
+	BEMID               *uint          `json:"bem_id,omitempty" gorm:"index"`
+	Title               string         `json:"title" gorm:"type:varchar(255);not null"`
+	Description         string         `json:"description" gorm:"type:text"`
+	StartDate           time.Time      `json:"start_date"`
+	EndDate             time.Time      `json:"end_date"`
+	Location            string         `json:"location" gorm:"type:varchar(255)"`
+	Capacity            int            `json:"capacity" gorm:"default:0;comment:0 means unlimited"`
+	RecurrenceFrequency string         `json:"recurrence_frequency,omitempty" gorm:"type:varchar(10);comment:weekly or monthly"`
+	RecurrenceInterval  int            `json:"recurrence_interval,omitempty" gorm:"default:1"`
+	RecurrenceUntil     *time.Time     `json:"recurrence_until,omitempty"`
+	RecurrenceCount     int            `json:"recurrence_count,omitempty" gorm:"default:0"`
+	SeriesEndDate       *time.Time     `json:"series_end_date,omitempty" gorm:"index;comment:end of the last occurrence of a recurring activity"`
+	Tags                []Tag          `json:"tags" gorm:"many2many:activity_tags;"`
+	CreatedAt           time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt           time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt           gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 func (Activity) TableName() string {
 	return "activities"
 }
 
+// Recurrence frequencies supported by Activity.RecurrenceFrequency.
+const (
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+)
+
+// ActivityOccurrence overrides or cancels a single occurrence of a recurring
+// activity. OccurrenceStart is the start the occurrence has according to the
+// series rule and identifies it; empty override fields inherit from the series.
+type ActivityOccurrence struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	ActivityID      uint       `json:"activity_id" gorm:"not null;uniqueIndex:idx_occurrence_activity_start"`
+	OccurrenceStart time.Time  `json:"occurrence_start" gorm:"not null;uniqueIndex:idx_occurrence_activity_start"`
+	Title           string     `json:"title,omitempty" gorm:"type:varchar(255)"`
+	Description     string     `json:"description,omitempty" gorm:"type:text"`
+	Location        string     `json:"location,omitempty" gorm:"type:varchar(255)"`
+	StartDate       *time.Time `json:"start_date,omitempty"`
+	EndDate         *time.Time `json:"end_date,omitempty"`
+	Cancelled       bool       `json:"cancelled" gorm:"default:false"`
+	CreatedAt       time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt       time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (ActivityOccurrence) TableName() string {
+	return "activity_occurrences"
+}
+
+// Proposal statuses, in the order a proposal moves through review.
+const (
+	ProposalStatusSubmitted      = "submitted"
+	ProposalStatusLeaderReviewed = "leader_reviewed"
+	ProposalStatusBEMReviewed    = "bem_reviewed"
+	ProposalStatusApproved       = "approved"
+	ProposalStatusRejected       = "rejected"
+)
+
 // Proposal represents a proposal document for an activity.
 type Proposal struct {
-	ID         uint           `json:"id" gorm:"primaryKey"`
-	ActivityID uint           `json:"activity_id" gorm:"not null"`
-	Activity   *Activity      `json:"activity,omitempty" gorm:"foreignKey:ActivityID"`
-	FilePath   string         `json:"file_path" gorm:"type:varchar(255);not null"`
-	Status     string         `json:"status" gorm:"type:varchar(20);default:'pending';comment:approved, pending, rejected"`
-	CreatedAt  time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt  time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+	ID            uint             `json:"id" gorm:"primaryKey"`
+	ActivityID    uint             `json:"activity_id" gorm:"not null;index"`
+	Activity      *Activity        `json:"activity,omitempty" gorm:"foreignKey:ActivityID"`
+	FilePath      string           `json:"file_path" gorm:"type:varchar(255);not null"`
+	Status        string           `json:"status" gorm:"type:varchar(20);default:'submitted';comment:submitted, leader_reviewed, bem_reviewed, approved, rejected"`
+	SubmittedByID uint             `json:"submitted_by_id"`
+	Reviews       []ProposalReview `json:"reviews,omitempty" gorm:"foreignKey:ProposalID"`
+	BudgetItems   []BudgetItem     `json:"budget_items,omitempty" gorm:"foreignKey:ProposalID"`
+	CreatedAt     time.Time        `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time        `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt     gorm.DeletedAt   `json:"-" gorm:"index"`
 }
 
 func (Proposal) TableName() string {
 	return "proposals"
 }
 
+// ProposalReview records a single status transition of a proposal.
+type ProposalReview struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ProposalID uint      `json:"proposal_id" gorm:"not null;index"`
+	FromStatus string    `json:"from_status" gorm:"type:varchar(20);not null"`
+	ToStatus   string    `json:"to_status" gorm:"type:varchar(20);not null"`
+	ReviewerID uint      `json:"reviewer_id" gorm:"not null"`
+	Comment    string    `json:"comment" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (ProposalReview) TableName() string {
+	return "proposal_reviews"
+}
+
+// BudgetItem is a planned budget line (RAB) attached to a proposal.
+// Actual spending is matched against it by Category.
+type BudgetItem struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	ProposalID  uint           `json:"proposal_id" gorm:"not null;index"`
+	Category    string         `json:"category" gorm:"type:varchar(100);not null"`
+	Description string         `json:"description" gorm:"type:text"`
+	Quantity    float64        `json:"quantity" gorm:"type:decimal(10,2);not null"`
+	Unit        string         `json:"unit" gorm:"type:varchar(30)"`
+	UnitPrice   float64        `json:"unit_price" gorm:"type:decimal(15,2);not null"`
+	Amount      float64        `json:"amount" gorm:"type:decimal(15,2);not null"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+func (BudgetItem) TableName() string {
+	return "budget_items"
+}
+
 // Report represents a final accountability report for an activity.
 type Report struct {
-	ID         uint           `json:"id" gorm:"primaryKey"`
-	ActivityID uint           `json:"activity_id" gorm:"not null"`
-	Activity   *Activity      `json:"activity,omitempty" gorm:"foreignKey:ActivityID"`
-	FilePath   string         `json:"file_path" gorm:"type:varchar(255);not null"`
-	CreatedAt  time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt  time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt  gorm.DeletedAt `json:"-" gorm:"index"`
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	ActivityID    uint           `json:"activity_id" gorm:"not null;index"`
+	Activity      *Activity      `json:"activity,omitempty" gorm:"foreignKey:ActivityID"`
+	FilePath      string         `json:"file_path" gorm:"type:varchar(255);not null"`
+	SubmittedByID uint           `json:"submitted_by_id"`
+	Deadline      time.Time      `json:"deadline"`
+	SubmittedLate bool           `json:"submitted_late" gorm:"default:false"`
+	CreatedAt     time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 func (Report) TableName() string {
@@ -58,6 +144,8 @@ func (Report) TableName() string {
 }
 
 // Finance tracks the finances for an activity.
+//
+// Deprecated: balances are now computed from FinanceEntry rows.
 type Finance struct {
 	ID         uint           `json:"id" gorm:"primaryKey"`
 	ActivityID uint           `json:"activity_id" gorm:"not null"`
@@ -73,3 +161,75 @@ type Finance struct {
 func (Finance) TableName() string {
 	return "finances"
 }
+
+// Finance entry types.
+const (
+	FinanceEntryIncome  = "income"
+	FinanceEntryExpense = "expense"
+)
+
+// FinanceEntry is a single dated income or expense transaction for an activity.
+type FinanceEntry struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	ActivityID   uint           `json:"activity_id" gorm:"not null;index"`
+	Activity     *Activity      `json:"activity,omitempty" gorm:"foreignKey:ActivityID"`
+	Type         string         `json:"type" gorm:"type:varchar(10);not null;comment:income, expense"`
+	Amount       float64        `json:"amount" gorm:"type:decimal(15,2);not null"`
+	Category     string         `json:"category" gorm:"type:varchar(100)"`
+	Description  string         `json:"description" gorm:"type:text"`
+	EntryDate    time.Time      `json:"entry_date" gorm:"not null"`
+	ReceiptPath  string         `json:"receipt_path,omitempty" gorm:"type:varchar(255)"`
+	RecordedByID uint           `json:"recorded_by_id"`
+	CreatedAt    time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+func (FinanceEntry) TableName() string {
+	return "finance_entries"
+}
+
+// Activity registration statuses.
+const (
+	RegistrationRegistered = "registered"
+	RegistrationWaitlisted = "waitlisted"
+	RegistrationCancelled  = "cancelled"
+)
+
+// ActivityRegistration is a student's sign-up (RSVP) for an activity.
+// Waitlisted registrations are promoted in RegisteredAt order.
+type ActivityRegistration struct {
+	ID           uint       `json:"id" gorm:"primaryKey"`
+	ActivityID   uint       `json:"activity_id" gorm:"not null;uniqueIndex:idx_registration_activity_student"`
+	Activity     *Activity  `json:"activity,omitempty" gorm:"foreignKey:ActivityID"`
+	StudentID    uint       `json:"student_id" gorm:"not null;uniqueIndex:idx_registration_activity_student"`
+	Student      *Student   `json:"student,omitempty" gorm:"foreignKey:StudentID"`
+	Status       string     `json:"status" gorm:"type:varchar(20);not null;comment:registered, waitlisted, cancelled"`
+	RegisteredAt time.Time  `json:"registered_at"`
+	PromotedAt   *time.Time `json:"promoted_at,omitempty"`
+	CancelledAt  *time.Time `json:"cancelled_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (ActivityRegistration) TableName() string {
+	return "activity_registrations"
+}
+
+// ActivityAttendance records a student's QR check-in at an activity.
+// A student can check in at most once per occurrence; OccurrenceStart equals
+// StartDate for one-off activities.
+type ActivityAttendance struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	ActivityID      uint      `json:"activity_id" gorm:"not null;uniqueIndex:idx_attendance_activity_occurrence_student"`
+	Activity        *Activity `json:"activity,omitempty" gorm:"foreignKey:ActivityID"`
+	OccurrenceStart time.Time `json:"occurrence_start" gorm:"not null;uniqueIndex:idx_attendance_activity_occurrence_student"`
+	StudentID       uint      `json:"student_id" gorm:"not null;uniqueIndex:idx_attendance_activity_occurrence_student"`
+	Student         *Student  `json:"student,omitempty" gorm:"foreignKey:StudentID"`
+	CheckedInAt     time.Time `json:"checked_in_at"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (ActivityAttendance) TableName() string {
+	return "activity_attendances"
+}
@@ -7,15 +7,19 @@ import (
 )
 
 type Announcement struct {
-    ID        uint           `json:"id" gorm:"primaryKey"`
-    Title     string         `json:"title" gorm:"size:255;not null"`
-    Content   string         `json:"content" gorm:"type:text;not null"`
-    FileURL   string         `json:"file_url,omitempty" gorm:"type:varchar(255);column:file_url"`
-    AuthorID  uint           `json:"author_id" gorm:"not null"`
-    Author    *User          `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
-    StartDate *time.Time     `json:"start_date,omitempty"`
-    EndDate   *time.Time     `json:"end_date,omitempty"`
-    CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
-    UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-    DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+    ID            uint           `json:"id" gorm:"primaryKey"`
+    Title         string         `json:"title" gorm:"size:255;not null"`
+    Slug          string         `json:"slug" gorm:"type:varchar(191);uniqueIndex"`
+    Content       string         `json:"content" gorm:"type:text;not null"`
+    FileURL       string         `json:"file_url,omitempty" gorm:"type:varchar(255);column:file_url"`
+    AuthorID      uint           `json:"author_id" gorm:"not null"`
+    Author        *User          `json:"author,omitempty" gorm:"foreignKey:AuthorID"`
+    BEMID         *uint          `json:"bem_id,omitempty" gorm:"index"`
+    AssociationID *uint          `json:"association_id,omitempty" gorm:"index"`
+    DepartmentID  *uint          `json:"department_id,omitempty" gorm:"index"`
+    StartDate     *time.Time     `json:"start_date,omitempty"`
+    EndDate       *time.Time     `json:"end_date,omitempty"`
+    CreatedAt     time.Time      `json:"created_at" gorm:"autoCreateTime"`
+    UpdatedAt     time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+    DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
 }
\ No newline at end of file
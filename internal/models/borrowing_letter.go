@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// BorrowingLetter is the official letter (surat peminjaman) issued for an
+// approved borrowing request. Numbers are sequential per year and a letter is
+// issued only once per request, so re-downloading keeps the same number.
+type BorrowingLetter struct {
+	ID               uint      `json:"id" gorm:"primaryKey"`
+	RequestID        uint      `json:"request_id" gorm:"not null;uniqueIndex"`
+	Request          *Request  `json:"request,omitempty" gorm:"foreignKey:RequestID"`
+	Year             int       `json:"year" gorm:"not null;uniqueIndex:idx_letter_year_sequence"`
+	Sequence         uint      `json:"sequence" gorm:"not null;uniqueIndex:idx_letter_year_sequence"`
+	Number           string    `json:"number" gorm:"type:varchar(100);not null;uniqueIndex"`
+	VerificationCode string    `json:"verification_code" gorm:"type:varchar(64);not null;uniqueIndex"`
+	IssuedAt         time.Time `json:"issued_at" gorm:"not null"`
+	CreatedAt        time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (BorrowingLetter) TableName() string {
+	return "borrowing_letters"
+}
+
+// LetterSequence holds the last letter number issued in a year. The row is
+// locked while numbering so concurrent letters never share a sequence.
+type LetterSequence struct {
+	Year         int  `json:"year" gorm:"primaryKey;autoIncrement:false"`
+	LastSequence uint `json:"last_sequence" gorm:"not null;default:0"`
+}
+
+func (LetterSequence) TableName() string {
+	return "letter_sequences"
+}
@@ -8,13 +8,17 @@ import (
 )
 
 type Galery struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	Title     string         `json:"title" gorm:"size:255;not null"`
-	Content   string         `json:"content" gorm:"not null"`
-	ImageURL  string         `json:"image_url" gorm:"type:varchar(255)"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index;uniqueIndex:idx_courses_code_deleted_at" json:"deleted_at,omitempty"`
+	ID            uint           `json:"id" gorm:"primaryKey"`
+	Title         string         `json:"title" gorm:"size:255;not null"`
+	Content       string         `json:"content" gorm:"not null"`
+	ImageURL      string         `json:"image_url" gorm:"type:varchar(255)"`
+	BEMID         *uint          `json:"bem_id,omitempty" gorm:"index"`
+	AssociationID *uint          `json:"association_id,omitempty" gorm:"index"`
+	DepartmentID  *uint          `json:"department_id,omitempty" gorm:"index"`
+	Tags          []Tag          `json:"tags" gorm:"many2many:galery_tags;"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt `gorm:"index;uniqueIndex:idx_courses_code_deleted_at" json:"deleted_at,omitempty"`
 }
 
 func (Galery) TableName() string {
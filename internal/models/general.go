@@ -6,6 +6,14 @@ import (
 	"gorm.io/gorm"
 )
 
+// News publication states. Scheduled news is published automatically once
+// PublishAt has passed.
+const (
+	NewsStatusDraft     = "draft"
+	NewsStatusScheduled = "scheduled"
+	NewsStatusPublished = "published"
+)
+
 // News represents an article or announcement.
 type News struct {
 	ID            uint           `json:"id" gorm:"primaryKey"`
@@ -13,9 +21,12 @@ type News struct {
 	AssociationID *uint          `json:"association_id,omitempty" gorm:"index"`
 	DepartmentID  *uint          `json:"department_id,omitempty" gorm:"index"`
 	Title         string         `json:"title" gorm:"type:varchar(255);not null"`
+	Slug          string         `json:"slug" gorm:"type:varchar(191);uniqueIndex"`
 	Content       string         `json:"content" gorm:"type:text;not null"`
-	Category      string         `json:"category" gorm:"type:varchar(100)"`
 	ImageURL      string         `json:"image_url" gorm:"type:varchar(255)"`
+	Status        string         `json:"status" gorm:"type:varchar(20);not null;default:'published';index"`
+	PublishAt     *time.Time     `json:"publish_at,omitempty" gorm:"index"`
+	Tags          []Tag          `json:"tags" gorm:"many2many:news_tags;"`
 	CreatedAt     time.Time      `json:"created_at" gorm:"autoCreateTime"`
 	UpdatedAt     time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
 	DeletedAt     gorm.DeletedAt `json:"-" gorm:"index"`
@@ -25,17 +36,118 @@ func (News) TableName() string {
 	return "news"
 }
 
+// Aspiration categories a student can tag a submission with.
+const (
+	AspirationCategoryAcademic = "akademik"
+	AspirationCategoryFacility = "fasilitas"
+	AspirationCategoryStudent  = "kemahasiswaan"
+	AspirationCategoryFinance  = "keuangan"
+	AspirationCategoryWelfare  = "kesejahteraan"
+	AspirationCategoryOther    = "lainnya"
+)
+
 // Aspiration represents feedback or suggestions from users.
+// The author is always stored, even for anonymous submissions, so abuse can be
+// traced; IsAnonymous only hides it from the admin inbox.
 type Aspiration struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	UserID    uint           `json:"user_id" gorm:"not null"`
-	User      *User          `json:"user,omitempty" gorm:"foreignKey:UserID"`
-	Content   string         `json:"content" gorm:"type:text;not null"`
-	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID             uint                      `json:"id" gorm:"primaryKey"`
+	UserID         uint                      `json:"user_id,omitempty" gorm:"not null"`
+	User           *User                     `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	StudentID      *uint                     `json:"student_id,omitempty" gorm:"index"`
+	Student        *Student                  `json:"student,omitempty" gorm:"foreignKey:StudentID"`
+	OrganizationID *uint                     `json:"organization_id,omitempty" gorm:"index;comment:target organization, empty for BEM"`
+	Organization   *Organization             `json:"organization,omitempty" gorm:"foreignKey:OrganizationID"`
+	Title          string                    `json:"title" gorm:"type:varchar(255)"`
+	Category       string                    `json:"category" gorm:"type:varchar(50);index"`
+	IsAnonymous    bool                      `json:"is_anonymous" gorm:"default:false"`
+	Content        string                    `json:"content" gorm:"type:text;not null"`
+	Status         string                    `json:"status" gorm:"type:varchar(20);default:received;index"`
+	VoteCount      int                       `json:"vote_count" gorm:"default:0"`
+	MergedIntoID   *uint                     `json:"merged_into_id,omitempty" gorm:"index;comment:set when folded into a duplicate aspiration"`
+	Moderation     string                    `json:"moderation_status" gorm:"column:moderation_status;type:varchar(20);default:approved;index"`
+	FlagReason     string                    `json:"flag_reason,omitempty" gorm:"type:varchar(255)"`
+	ModeratedByID  *uint                     `json:"moderated_by_id,omitempty"`
+	ModeratedAt    *time.Time                `json:"moderated_at,omitempty"`
+	ModerationNote string                    `json:"moderation_note,omitempty" gorm:"type:text"`
+	Responses      []AspirationResponse      `json:"responses,omitempty" gorm:"foreignKey:AspirationID"`
+	StatusHistory  []AspirationStatusHistory `json:"status_history,omitempty" gorm:"foreignKey:AspirationID"`
+	CreatedAt      time.Time                 `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time                 `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt            `json:"-" gorm:"index"`
 }
 
 func (Aspiration) TableName() string {
 	return "aspirations"
 }
+
+// Aspiration statuses, in the order an aspiration is usually handled.
+const (
+	AspirationStatusReceived  = "received"
+	AspirationStatusInReview  = "in_review"
+	AspirationStatusForwarded = "forwarded"
+	AspirationStatusResolved  = "resolved"
+	AspirationStatusDeclined  = "declined"
+)
+
+// Aspiration moderation states. Submissions caught by the content filter wait in
+// the moderation queue as pending; only approved aspirations are shown publicly.
+const (
+	AspirationModerationApproved = "approved"
+	AspirationModerationPending  = "pending"
+	AspirationModerationHidden   = "hidden"
+	AspirationModerationAbusive  = "abusive"
+)
+
+// AspirationResponse is an official reply in an aspiration's thread.
+type AspirationResponse struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	AspirationID uint      `json:"aspiration_id" gorm:"not null;index"`
+	ResponderID  uint      `json:"responder_id" gorm:"not null"`
+	Content      string    `json:"content" gorm:"type:text;not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (AspirationResponse) TableName() string {
+	return "aspiration_responses"
+}
+
+// AspirationStatusHistory records a single status change of an aspiration.
+type AspirationStatusHistory struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	AspirationID uint      `json:"aspiration_id" gorm:"not null;index"`
+	FromStatus   string    `json:"from_status" gorm:"type:varchar(20)"`
+	ToStatus     string    `json:"to_status" gorm:"type:varchar(20);not null"`
+	ChangedByID  uint      `json:"changed_by_id"`
+	Note         string    `json:"note" gorm:"type:text"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (AspirationStatusHistory) TableName() string {
+	return "aspiration_status_histories"
+}
+
+// AspirationVote is a student's upvote on an aspiration; one per student.
+type AspirationVote struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	AspirationID uint      `json:"aspiration_id" gorm:"not null;uniqueIndex:idx_vote_aspiration_student"`
+	StudentID    uint      `json:"student_id" gorm:"not null;uniqueIndex:idx_vote_aspiration_student"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime;index"`
+}
+
+func (AspirationVote) TableName() string {
+	return "aspiration_votes"
+}
+
+// AspirationAuthorAccess is an audit record written whenever an admin reveals
+// the author of an anonymous aspiration.
+type AspirationAuthorAccess struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	AspirationID uint      `json:"aspiration_id" gorm:"not null;index"`
+	AdminID      uint      `json:"admin_id" gorm:"not null"`
+	Reason       string    `json:"reason" gorm:"type:text;not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (AspirationAuthorAccess) TableName() string {
+	return "aspiration_author_accesses"
+}
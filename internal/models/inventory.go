@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Inventory item conditions. Damaged items cannot be borrowed.
+const (
+	InventoryConditionGood    = "good"
+	InventoryConditionFair    = "fair"
+	InventoryConditionDamaged = "damaged"
+)
+
+// InventoryItem is an asset owned by the BEM that organizations can borrow.
+// Availability is not stored; it is computed from approved borrowing requests
+// that overlap the requested window.
+type InventoryItem struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"type:varchar(255);not null;uniqueIndex"`
+	Description string         `json:"description" gorm:"type:text"`
+	TotalStock  uint           `json:"total_stock" gorm:"not null;default:0"`
+	Condition   string         `json:"condition" gorm:"type:varchar(20);default:good"`
+	Location    string         `json:"location" gorm:"type:varchar(255);comment:where the item is stored"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+func (InventoryItem) TableName() string {
+	return "inventory_items"
+}
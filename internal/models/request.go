@@ -6,9 +6,23 @@ import (
 	"gorm.io/gorm"
 )
 
+// Borrowing request statuses. A pending request is approved or rejected by an
+// admin; pending and approved requests can still be cancelled. Approved items
+// are then handed over and finally returned.
+const (
+	RequestStatusPending    = "pending"
+	RequestStatusApproved   = "approved"
+	RequestStatusRejected   = "rejected"
+	RequestStatusCancelled  = "cancelled"
+	RequestStatusHandedOver = "handed_over"
+	RequestStatusReturned   = "returned"
+)
+
 type Request struct {
 	ID               uint           `json:"id" gorm:"primaryKey"`
 	Name             string         `json:"name" gorm:"size:255;not null"`
+	ItemID           *uint          `json:"item_id,omitempty" gorm:"index"`
+	Item             *InventoryItem `json:"item,omitempty" gorm:"foreignKey:ItemID"`
 	Quantity         uint           `json:"quantity" gorm:"not null"`
 	RequestPlan      string         `json:"request_plan" gorm:"not null"`
 	ReturnPlan       string         `json:"return_plan" gorm:"not null"`
@@ -16,12 +30,25 @@ type Request struct {
 	ApproverID       *uint          `json:"approver_id" gorm:"default:null"`
 	Requester        *User          `json:"requester,omitempty" gorm:"foreignKey:RequesterID"`
 	Approver         *User          `json:"approver,omitempty" gorm:"foreignKey:ApproverID"`
-	Status           string         `json:"status" gorm:"type:enum('pending', 'approved', 'rejected');default:'pending';not null"`
+	Status           string         `json:"status" gorm:"type:enum('pending', 'approved', 'rejected', 'cancelled', 'handed_over', 'returned');default:'pending';not null"`
+	StatusReason     string         `json:"status_reason,omitempty" gorm:"type:text"`
+	DecidedAt        *time.Time     `json:"decided_at,omitempty"`
+	CancelledByID    *uint          `json:"cancelled_by_id,omitempty" gorm:"default:null"`
+	CancelledAt      *time.Time     `json:"cancelled_at,omitempty"`
+	HandedOverByID   *uint          `json:"handed_over_by_id,omitempty" gorm:"default:null"`
+	HandedOverAt     *time.Time     `json:"handed_over_at,omitempty"`
+	HandOverNote     string         `json:"hand_over_note,omitempty" gorm:"type:text"`
+	ReturnedByID     *uint          `json:"returned_by_id,omitempty" gorm:"default:null;comment:admin who confirmed the return"`
+	ReturnedAt       *time.Time     `json:"returned_at,omitempty"`
+	ReturnCondition  string         `json:"return_condition,omitempty" gorm:"type:text"`
 	CreatedAt        time.Time      `json:"created_at"`
 	UpdatedAt        time.Time      `json:"updated_at"`
 	DeletedAt        gorm.DeletedAt `gorm:"index;uniqueIndex:idx_courses_code_deleted_at" json:"deleted_at,omitempty"`
 	OrganizationID   int            `json:"organization_id"`
 	OrganizationName string         `json:"organization_name"`
+	// OrganizationOverdue is set on open requests whose organization still has
+	// overdue items that have not been returned.
+	OrganizationOverdue bool `json:"organization_overdue" gorm:"-"`
 }
 
 func (Request) TableName() string {
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// Content types that can own a slug redirect.
+const (
+	SlugEntityNews         = "news"
+	SlugEntityAnnouncement = "announcement"
+)
+
+// SlugRedirect remembers a slug that was replaced after a title edit, so links
+// shared with the old slug still resolve to the same item.
+type SlugRedirect struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	EntityType string    `json:"entity_type" gorm:"type:varchar(20);not null;uniqueIndex:idx_slug_redirect"`
+	Slug       string    `json:"slug" gorm:"type:varchar(191);not null;uniqueIndex:idx_slug_redirect"`
+	EntityID   uint      `json:"entity_id" gorm:"not null;index"`
+	CreatedAt  time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+func (SlugRedirect) TableName() string {
+	return "slug_redirects"
+}
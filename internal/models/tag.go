@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// Tag is a managed label for news, gallery items and activities. Names are
+// matched by slug, so "Acara" and "acara" always resolve to the same tag.
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null;uniqueIndex"`
+	Slug      string    `json:"slug" gorm:"type:varchar(120);not null;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+func (Tag) TableName() string {
+	return "tags"
+}
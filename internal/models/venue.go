@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Venue represents a bookable location that activities are held at.
+// Activities reference a venue by matching Activity.Location against Name.
+type Venue struct {
+	ID          uint           `json:"id" gorm:"primaryKey"`
+	Name        string         `json:"name" gorm:"type:varchar(255);not null;uniqueIndex"`
+	Description string         `json:"description" gorm:"type:text"`
+	Exclusive   bool           `json:"exclusive" gorm:"default:false;comment:overlapping bookings are refused instead of warned"`
+	CreatedAt   time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt   time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+func (Venue) TableName() string {
+	return "venues"
+}
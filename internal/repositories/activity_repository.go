@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ActivityFilter berisi kriteria penyaringan daftar kegiatan.
+type ActivityFilter struct {
+	// OrganizationID mencocokkan himpunan maupun departemen dengan ID tersebut
+	OrganizationID *uint
+	DepartmentID   *uint
+	AssociationID  *uint
+	BEMID          *uint
+	From           *time.Time
+	To             *time.Time
+	// Tag adalah slug tag kegiatan
+	Tag string
+}
+
+// ActivityRepository adalah repository untuk operasi terkait kegiatan.
+type ActivityRepository struct {
+	db *gorm.DB
+}
+
+// NewActivityRepository membuat instance activity repository baru.
+func NewActivityRepository() *ActivityRepository {
+	return &ActivityRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create membuat kegiatan baru.
+func (r *ActivityRepository) Create(activity *models.Activity) error {
+	return r.db.Create(activity).Error
+}
+
+// Update menyimpan perubahan pada kegiatan yang ada.
+func (r *ActivityRepository) Update(activity *models.Activity) error {
+	return r.db.Save(activity).Error
+}
+
+// FindByID mencari kegiatan berdasarkan ID (hanya yang aktif).
+func (r *ActivityRepository) FindByID(id uint) (*models.Activity, error) {
+	var activity models.Activity
+	err := r.db.Preload("Tags").First(&activity, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// GetAllActivities mengambil kegiatan sesuai filter dengan pagination.
+func (r *ActivityRepository) GetAllActivities(filter ActivityFilter, limit, offset int) ([]models.Activity, int64, error) {
+	var activities []models.Activity
+	var total int64
+
+	query := applyActivityFilter(r.db.Model(&models.Activity{}), filter)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("Tags").Order("start_date ASC").Limit(limit).Offset(offset).Find(&activities).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return activities, total, nil
+}
+
+// applyActivityFilter menerapkan filter penyelenggara, tag, dan rentang tanggal ke query.
+// Kegiatan dianggap masuk rentang jika waktunya beririsan dengan From..To;
+// untuk kegiatan berulang dipakai akhir rangkaian (series_end_date).
+func applyActivityFilter(query *gorm.DB, filter ActivityFilter) *gorm.DB {
+	if filter.OrganizationID != nil {
+		query = query.Where("(association_id = ? OR department_id = ?)", *filter.OrganizationID, *filter.OrganizationID)
+	}
+	if filter.DepartmentID != nil {
+		query = query.Where("department_id = ?", *filter.DepartmentID)
+	}
+	if filter.AssociationID != nil {
+		query = query.Where("association_id = ?", *filter.AssociationID)
+	}
+	if filter.BEMID != nil {
+		query = query.Where("bem_id = ?", *filter.BEMID)
+	}
+	if filter.From != nil {
+		query = query.Where("COALESCE(series_end_date, end_date) >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("start_date <= ?", *filter.To)
+	}
+	if filter.Tag != "" {
+		query = query.Scopes(taggedWith("activity_tags", filter.Tag))
+	}
+	return query
+}
+
+// FindAll mengambil semua kegiatan sesuai filter tanpa pagination.
+func (r *ActivityRepository) FindAll(filter ActivityFilter) ([]models.Activity, error) {
+	var activities []models.Activity
+	err := applyActivityFilter(r.db.Model(&models.Activity{}), filter).Order("start_date ASC").Find(&activities).Error
+	return activities, err
+}
+
+// FindOverlapping mengambil kegiatan lain di lokasi yang sama yang waktunya (atau rangkaian
+// kegiatan berulangnya) beririsan dengan start..end, termasuk kegiatan yang salah satu
+// pertemuannya dipindahkan ke lokasi atau waktu tersebut.
+// Lokasi dibandingkan tanpa membedakan huruf besar/kecil dan spasi di awal/akhir.
+func (r *ActivityRepository) FindOverlapping(location string, start, end time.Time, excludeID uint) ([]models.Activity, error) {
+	var activities []models.Activity
+	// Pertemuan tanpa waktu selesai pengganti tetap selesai pada waktu aslinya (lihat applyOverride)
+	moved := r.db.Table("activity_occurrences AS o").
+		Select("o.activity_id").
+		Joins("JOIN activities AS a ON a.id = o.activity_id").
+		Where("o.cancelled = ?", false).
+		Where("LOWER(TRIM(COALESCE(NULLIF(o.location, ''), a.location))) = ?", location).
+		Where("COALESCE(o.start_date, o.occurrence_start) < ?", end).
+		Where("COALESCE(o.end_date, TIMESTAMPADD(SECOND, TIMESTAMPDIFF(SECOND, a.start_date, a.end_date), o.occurrence_start)) > ?", start)
+	query := r.db.Where("((LOWER(TRIM(location)) = ? AND start_date < ? AND COALESCE(series_end_date, end_date) > ?) OR id IN (?))",
+		location, end, start, moved)
+	if excludeID != 0 {
+		query = query.Where("id <> ?", excludeID)
+	}
+	err := query.Order("start_date ASC").Find(&activities).Error
+	return activities, err
+}
+
+// DeleteByID menghapus kegiatan berdasarkan ID (soft delete).
+func (r *ActivityRepository) DeleteByID(id uint) error {
+	return r.db.Delete(&models.Activity{}, id).Error
+}
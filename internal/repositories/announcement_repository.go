@@ -3,6 +3,8 @@ package repositories
 import (
 	"bem_be/internal/database"
 	"bem_be/internal/models"
+	"time"
+
 	"gorm.io/gorm"
 )
 
@@ -38,6 +40,57 @@ func (r *AnnouncementRepository) FindByID(id uint) (*models.Announcement, error)
 	return &announcement, nil
 }
 
+// activeAnnouncements limits a query to announcements shown to the public at now:
+// those whose StartDate has passed and whose EndDate has not, where set
+func activeAnnouncements(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(start_date IS NULL OR start_date <= ?) AND (end_date IS NULL OR end_date >= ?)", now, now)
+	}
+}
+
+// FindActiveBySlug finds an announcement that is currently shown to the public by slug
+func (r *AnnouncementRepository) FindActiveBySlug(slug string) (*models.Announcement, error) {
+	var announcement models.Announcement
+	err := r.db.Scopes(activeAnnouncements(time.Now())).Where("slug = ?", slug).First(&announcement).Error
+	if err != nil {
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+// FindActiveByID finds an announcement that is currently shown to the public by ID
+func (r *AnnouncementRepository) FindActiveByID(id uint) (*models.Announcement, error) {
+	var announcement models.Announcement
+	err := r.db.Scopes(activeAnnouncements(time.Now())).First(&announcement, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+// SlugExists checks if a slug is used by another announcement, including soft-deleted records
+func (r *AnnouncementRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Unscoped().Model(&models.Announcement{}).Where("slug = ?", slug)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// FindWithoutSlug finds announcements that have no slug yet, including soft-deleted records
+func (r *AnnouncementRepository) FindWithoutSlug() ([]models.Announcement, error) {
+	var announcements []models.Announcement
+	err := r.db.Unscoped().Where("slug IS NULL OR slug = ''").Order("id ASC").Find(&announcements).Error
+	return announcements, err
+}
+
+// UpdateSlug updates only the slug of an announcement
+func (r *AnnouncementRepository) UpdateSlug(id uint, slug string) error {
+	return r.db.Unscoped().Model(&models.Announcement{}).Where("id = ?", id).UpdateColumn("slug", slug).Error
+}
+
 // FindByName finds a announcement by code
 func (r *AnnouncementRepository) FindByName(code string) (*models.Announcement, error) {
 	var announcement models.Announcement
@@ -51,12 +104,15 @@ func (r *AnnouncementRepository) FindByName(code string) (*models.Announcement,
 	return &announcement, nil
 }
 
-// FindAll finds all announcements
-func (r *AnnouncementRepository) GetAllAnnouncements(limit, offset int) ([]models.Announcement, int64, error) {
+// FindAll finds all announcements, limited to the owners in scope when owners is set
+func (r *AnnouncementRepository) GetAllAnnouncements(owners *OwnerScope, limit, offset int) ([]models.Announcement, int64, error) {
     var announcements []models.Announcement
     var total int64
 
     query := r.db.Model(&models.Announcement{})
+    if owners != nil {
+        query = query.Scopes(ownedBy(*owners))
+    }
     if err := query.Count(&total).Error; err != nil {
         return nil, 0, err
     }
@@ -124,4 +180,10 @@ func (r *AnnouncementRepository) RestoreByName(code string) (*models.Announcemen
 // 	}
 	
 // 	return count > 0, nil
-// } 
\ No newline at end of file
+// } 
+// FindScheduled finds announcements that have both a start and an end date
+func (r *AnnouncementRepository) FindScheduled() ([]models.Announcement, error) {
+	var announcements []models.Announcement
+	err := r.db.Where("start_date IS NOT NULL AND end_date IS NOT NULL").Order("start_date ASC").Find(&announcements).Error
+	return announcements, err
+}
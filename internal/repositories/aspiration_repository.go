@@ -0,0 +1,288 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AspirationFilter berisi kriteria penyaringan kotak masuk aspirasi.
+type AspirationFilter struct {
+	Status         string
+	Moderation     string
+	Category       string
+	OrganizationID *uint
+	Anonymous      *bool
+	Search         string
+	From           *time.Time
+	To             *time.Time
+}
+
+// AspirationRepository adalah repository untuk operasi terkait aspirasi mahasiswa.
+type AspirationRepository struct {
+	db *gorm.DB
+}
+
+// NewAspirationRepository membuat instance aspiration repository baru.
+func NewAspirationRepository() *AspirationRepository {
+	return &AspirationRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Transaction menjalankan fn dengan repository yang terikat pada satu transaksi database.
+func (r *AspirationRepository) Transaction(fn func(tx *AspirationRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&AspirationRepository{db: tx})
+	})
+}
+
+// LockStudent mengunci data mahasiswa agar pengiriman aspirasi bersamaan dari mahasiswa
+// yang sama dihitung satu per satu oleh batas pengiriman.
+func (r *AspirationRepository) LockStudent(studentID uint) error {
+	var student models.Student
+	return r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&student, studentID).Error
+}
+
+// Create menyimpan aspirasi baru beserta riwayat status awalnya dalam satu transaksi.
+// Riwayat awal tidak mencatat pengirim agar aspirasi anonim tetap anonim.
+func (r *AspirationRepository) Create(aspiration *models.Aspiration) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(aspiration).Error; err != nil {
+			return err
+		}
+		history := models.AspirationStatusHistory{
+			AspirationID: aspiration.ID,
+			ToStatus:     aspiration.Status,
+		}
+		return tx.Create(&history).Error
+	})
+}
+
+// FindByID mencari aspirasi berdasarkan ID beserta organisasi tujuan, pengirim,
+// tanggapan, dan riwayat statusnya (terurut dari yang terlama).
+func (r *AspirationRepository) FindByID(id uint) (*models.Aspiration, error) {
+	var aspiration models.Aspiration
+	err := r.db.Preload("Organization").Preload("Student").
+		Preload("Responses", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC").Order("id ASC")
+		}).
+		Preload("StatusHistory", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC").Order("id ASC")
+		}).
+		First(&aspiration, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &aspiration, nil
+}
+
+// applyAspirationFilter menerapkan filter kotak masuk ke query.
+func applyAspirationFilter(query *gorm.DB, filter AspirationFilter) *gorm.DB {
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Moderation != "" {
+		query = query.Where("moderation_status = ?", filter.Moderation)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+	if filter.OrganizationID != nil {
+		query = query.Where("organization_id = ?", *filter.OrganizationID)
+	}
+	if filter.Anonymous != nil {
+		query = query.Where("is_anonymous = ?", *filter.Anonymous)
+	}
+	if filter.Search != "" {
+		like := "%" + filter.Search + "%"
+		query = query.Where("(title LIKE ? OR content LIKE ?)", like, like)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at <= ?", *filter.To)
+	}
+	return query
+}
+
+// GetAll mengambil aspirasi sesuai filter dengan pagination, terbaru lebih dulu.
+func (r *AspirationRepository) GetAll(filter AspirationFilter, limit, offset int) ([]models.Aspiration, int64, error) {
+	var aspirations []models.Aspiration
+	var total int64
+
+	query := applyAspirationFilter(r.db.Model(&models.Aspiration{}), filter)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Organization").Preload("Student").
+		Order("created_at DESC").Limit(limit).Offset(offset).Find(&aspirations).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return aspirations, total, nil
+}
+
+// GetByStudentID mengambil aspirasi milik seorang mahasiswa dengan pagination, terbaru lebih dulu.
+func (r *AspirationRepository) GetByStudentID(studentID uint, limit, offset int) ([]models.Aspiration, int64, error) {
+	var aspirations []models.Aspiration
+	var total int64
+
+	query := r.db.Model(&models.Aspiration{}).Where("student_id = ?", studentID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Organization").
+		Order("created_at DESC").Limit(limit).Offset(offset).Find(&aspirations).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return aspirations, total, nil
+}
+
+// GetSubmissionTimesSince mengambil waktu pengiriman aspirasi seorang mahasiswa sejak waktu tertentu,
+// terbaru lebih dulu. Aspirasi yang sudah dihapus ikut dihitung agar batas pengiriman tidak bisa diakali.
+func (r *AspirationRepository) GetSubmissionTimesSince(studentID uint, since time.Time) ([]time.Time, error) {
+	var times []time.Time
+	err := r.db.Unscoped().Model(&models.Aspiration{}).
+		Where("student_id = ? AND created_at >= ?", studentID, since).
+		Order("created_at DESC").Pluck("created_at", &times).Error
+	return times, err
+}
+
+// SaveModeration menyimpan hasil moderasi aspirasi.
+func (r *AspirationRepository) SaveModeration(aspiration *models.Aspiration) error {
+	return r.db.Model(aspiration).Select("moderation_status", "moderated_by_id", "moderated_at", "moderation_note").
+		Updates(aspiration).Error
+}
+
+// SaveStatusChange memperbarui status aspirasi dan mencatat riwayatnya dalam satu transaksi.
+func (r *AspirationRepository) SaveStatusChange(aspiration *models.Aspiration, history *models.AspirationStatusHistory) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(aspiration).Update("status", aspiration.Status).Error; err != nil {
+			return err
+		}
+		return tx.Create(history).Error
+	})
+}
+
+// CreateResponse menambahkan tanggapan resmi ke utas aspirasi.
+func (r *AspirationRepository) CreateResponse(response *models.AspirationResponse) error {
+	return r.db.Create(response).Error
+}
+
+// FindVote mengambil dukungan seorang mahasiswa pada sebuah aspirasi.
+func (r *AspirationRepository) FindVote(aspirationID, studentID uint) (*models.AspirationVote, error) {
+	var vote models.AspirationVote
+	err := r.db.Where("aspiration_id = ? AND student_id = ?", aspirationID, studentID).First(&vote).Error
+	if err != nil {
+		return nil, err
+	}
+	return &vote, nil
+}
+
+// AddVote mencatat dukungan dan menambah jumlah dukungan aspirasi dalam satu transaksi.
+func (r *AspirationRepository) AddVote(vote *models.AspirationVote) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(vote).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Aspiration{}).Where("id = ?", vote.AspirationID).
+			Update("vote_count", gorm.Expr("vote_count + 1")).Error
+	})
+}
+
+// RemoveVote menghapus dukungan dan mengurangi jumlah dukungan aspirasi dalam satu transaksi.
+func (r *AspirationRepository) RemoveVote(vote *models.AspirationVote) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(vote)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return tx.Model(&models.Aspiration{}).Where("id = ? AND vote_count > 0", vote.AspirationID).
+			Update("vote_count", gorm.Expr("vote_count - 1")).Error
+	})
+}
+
+// GetVotesSince mengambil dukungan sejak waktu tertentu untuk aspirasi yang sudah disetujui moderasi,
+// belum digabung, dan statusnya tidak termasuk excludedStatuses.
+func (r *AspirationRepository) GetVotesSince(since time.Time, excludedStatuses []string) ([]models.AspirationVote, error) {
+	var votes []models.AspirationVote
+	query := r.db.Model(&models.AspirationVote{}).
+		Joins("JOIN aspirations ON aspirations.id = aspiration_votes.aspiration_id AND aspirations.deleted_at IS NULL").
+		Where("aspiration_votes.created_at >= ?", since).
+		Where("aspirations.merged_into_id IS NULL").
+		Where("aspirations.moderation_status = ?", models.AspirationModerationApproved)
+	if len(excludedStatuses) > 0 {
+		query = query.Where("aspirations.status NOT IN ?", excludedStatuses)
+	}
+	err := query.Select("aspiration_votes.*").Find(&votes).Error
+	return votes, err
+}
+
+// FindByIDs mengambil aspirasi berdasarkan daftar ID beserta organisasi tujuannya.
+func (r *AspirationRepository) FindByIDs(ids []uint) ([]models.Aspiration, error) {
+	var aspirations []models.Aspiration
+	if len(ids) == 0 {
+		return aspirations, nil
+	}
+	err := r.db.Preload("Organization").Where("id IN ?", ids).Find(&aspirations).Error
+	return aspirations, err
+}
+
+// MergeInto menggabungkan aspirasi sumber ke aspirasi tujuan dalam satu transaksi.
+// Dukungan dipindahkan ke tujuan tanpa menghitung dua kali mahasiswa yang mendukung keduanya,
+// dan aspirasi yang sebelumnya digabung ke sumber ikut diarahkan ke tujuan.
+func (r *AspirationRepository) MergeInto(source, target *models.Aspiration) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var targetVoters []uint
+		if err := tx.Model(&models.AspirationVote{}).Where("aspiration_id = ?", target.ID).
+			Pluck("student_id", &targetVoters).Error; err != nil {
+			return err
+		}
+		if len(targetVoters) > 0 {
+			if err := tx.Where("aspiration_id = ? AND student_id IN ?", source.ID, targetVoters).
+				Delete(&models.AspirationVote{}).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&models.AspirationVote{}).Where("aspiration_id = ?", source.ID).
+			Update("aspiration_id", target.ID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.Aspiration{}).Where("merged_into_id = ?", source.ID).
+			Update("merged_into_id", target.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(source).Updates(map[string]interface{}{
+			"merged_into_id": target.ID,
+			"vote_count":     0,
+		}).Error; err != nil {
+			return err
+		}
+
+		var count int64
+		if err := tx.Model(&models.AspirationVote{}).Where("aspiration_id = ?", target.ID).Count(&count).Error; err != nil {
+			return err
+		}
+		target.VoteCount = int(count)
+		return tx.Model(target).Update("vote_count", count).Error
+	})
+}
+
+// LogAuthorAccess mencatat pembukaan identitas pengirim aspirasi anonim oleh admin.
+func (r *AspirationRepository) LogAuthorAccess(access *models.AspirationAuthorAccess) error {
+	return r.db.Create(access).Error
+}
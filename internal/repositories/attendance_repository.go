@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AttendanceRepository adalah repository untuk operasi terkait presensi kegiatan.
+type AttendanceRepository struct {
+	db *gorm.DB
+}
+
+// NewAttendanceRepository membuat instance attendance repository baru.
+func NewAttendanceRepository() *AttendanceRepository {
+	return &AttendanceRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create mencatat presensi baru.
+func (r *AttendanceRepository) Create(attendance *models.ActivityAttendance) error {
+	return r.db.Create(attendance).Error
+}
+
+// FindByOccurrenceAndStudent mengambil presensi seorang mahasiswa pada satu pertemuan kegiatan.
+func (r *AttendanceRepository) FindByOccurrenceAndStudent(activityID uint, occurrenceStart time.Time, studentID uint) (*models.ActivityAttendance, error) {
+	var attendance models.ActivityAttendance
+	err := r.db.Where("activity_id = ? AND occurrence_start = ? AND student_id = ?", activityID, occurrenceStart, studentID).
+		First(&attendance).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attendance, nil
+}
+
+// GetByActivityID mengambil presensi sebuah kegiatan beserta data mahasiswanya,
+// opsional hanya untuk satu pertemuan.
+func (r *AttendanceRepository) GetByActivityID(activityID uint, occurrenceStart *time.Time) ([]models.ActivityAttendance, error) {
+	var attendances []models.ActivityAttendance
+	query := r.db.Preload("Student").Where("activity_id = ?", activityID)
+	if occurrenceStart != nil {
+		query = query.Where("occurrence_start = ?", *occurrenceStart)
+	}
+	err := query.Order("checked_in_at ASC").Find(&attendances).Error
+	return attendances, err
+}
+
+// GetOccurrenceStarts mengambil waktu mulai pertemuan yang sudah memiliki presensi.
+func (r *AttendanceRepository) GetOccurrenceStarts(activityID uint) ([]time.Time, error) {
+	var starts []time.Time
+	err := r.db.Model(&models.ActivityAttendance{}).
+		Where("activity_id = ?", activityID).
+		Distinct().
+		Pluck("occurrence_start", &starts).Error
+	return starts, err
+}
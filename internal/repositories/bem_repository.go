@@ -39,6 +39,16 @@ func (r *BemRepository) FindByID(id uint) (*models.BEM, error) {
 	return &bem, nil
 }
 
+// FindLatest finds the most recently created bem, which is the current board
+func (r *BemRepository) FindLatest() (*models.BEM, error) {
+	var bem models.BEM
+	err := r.db.Order("id DESC").First(&bem).Error
+	if err != nil {
+		return nil, err
+	}
+	return &bem, nil
+}
+
 // FindByName finds a bem by code
 func (r *BemRepository) FindByName(code string) (*models.BEM, error) {
 	var bem models.BEM
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// BudgetRepository adalah repository untuk operasi terkait rencana anggaran (RAB).
+type BudgetRepository struct {
+	db *gorm.DB
+}
+
+// NewBudgetRepository membuat instance budget repository baru.
+func NewBudgetRepository() *BudgetRepository {
+	return &BudgetRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Transaction menjalankan fn dengan repository yang terikat pada satu transaksi database.
+func (r *BudgetRepository) Transaction(fn func(tx *BudgetRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&BudgetRepository{db: tx})
+	})
+}
+
+// Create membuat baris anggaran baru.
+func (r *BudgetRepository) Create(item *models.BudgetItem) error {
+	return r.db.Create(item).Error
+}
+
+// Update menyimpan perubahan pada baris anggaran yang ada.
+func (r *BudgetRepository) Update(item *models.BudgetItem) error {
+	return r.db.Save(item).Error
+}
+
+// FindByID mencari baris anggaran berdasarkan ID.
+func (r *BudgetRepository) FindByID(id uint) (*models.BudgetItem, error) {
+	var item models.BudgetItem
+	err := r.db.First(&item, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetByProposalID mengambil semua baris anggaran milik sebuah proposal.
+func (r *BudgetRepository) GetByProposalID(proposalID uint) ([]models.BudgetItem, error) {
+	var items []models.BudgetItem
+	err := r.db.Where("proposal_id = ?", proposalID).Order("id ASC").Find(&items).Error
+	return items, err
+}
+
+// DeleteByID menghapus baris anggaran berdasarkan ID (soft delete).
+func (r *BudgetRepository) DeleteByID(id uint) error {
+	return r.db.Delete(&models.BudgetItem{}, id).Error
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// FinanceRepository adalah repository untuk operasi terkait buku kas kegiatan.
+type FinanceRepository struct {
+	db *gorm.DB
+}
+
+// NewFinanceRepository membuat instance finance repository baru.
+func NewFinanceRepository() *FinanceRepository {
+	return &FinanceRepository{
+		db: database.GetDB(),
+	}
+}
+
+// ActivityTotals berisi total pemasukan dan pengeluaran sebuah kegiatan.
+type ActivityTotals struct {
+	ActivityID uint    `json:"activity_id"`
+	Income     float64 `json:"income"`
+	Expense    float64 `json:"expense"`
+}
+
+// CategoryTotal berisi total nominal transaksi untuk satu kategori.
+type CategoryTotal struct {
+	Category string  `json:"category"`
+	Total    float64 `json:"total"`
+}
+
+// Create membuat transaksi baru.
+func (r *FinanceRepository) Create(entry *models.FinanceEntry) error {
+	return r.db.Create(entry).Error
+}
+
+// Update menyimpan perubahan pada transaksi yang ada.
+func (r *FinanceRepository) Update(entry *models.FinanceEntry) error {
+	return r.db.Save(entry).Error
+}
+
+// FindByID mencari transaksi berdasarkan ID.
+func (r *FinanceRepository) FindByID(id uint) (*models.FinanceEntry, error) {
+	var entry models.FinanceEntry
+	err := r.db.First(&entry, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// GetByActivityID mengambil semua transaksi kegiatan, urut berdasarkan tanggal.
+func (r *FinanceRepository) GetByActivityID(activityID uint) ([]models.FinanceEntry, error) {
+	var entries []models.FinanceEntry
+	err := r.db.Where("activity_id = ?", activityID).Order("entry_date ASC").Order("id ASC").Find(&entries).Error
+	return entries, err
+}
+
+// SumByActivityIDs menghitung total pemasukan dan pengeluaran untuk setiap kegiatan.
+func (r *FinanceRepository) SumByActivityIDs(activityIDs []uint) ([]ActivityTotals, error) {
+	var totals []ActivityTotals
+	if len(activityIDs) == 0 {
+		return totals, nil
+	}
+
+	err := r.db.Model(&models.FinanceEntry{}).
+		Select("activity_id, "+
+			"COALESCE(SUM(CASE WHEN type = ? THEN amount ELSE 0 END), 0) AS income, "+
+			"COALESCE(SUM(CASE WHEN type = ? THEN amount ELSE 0 END), 0) AS expense",
+			models.FinanceEntryIncome, models.FinanceEntryExpense).
+		Where("activity_id IN ?", activityIDs).
+		Group("activity_id").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// SumExpensesByCategory menghitung total pengeluaran kegiatan per kategori.
+func (r *FinanceRepository) SumExpensesByCategory(activityID uint) ([]CategoryTotal, error) {
+	var totals []CategoryTotal
+	err := r.db.Model(&models.FinanceEntry{}).
+		Select("category, COALESCE(SUM(amount), 0) AS total").
+		Where("activity_id = ? AND type = ?", activityID, models.FinanceEntryExpense).
+		Group("category").
+		Scan(&totals).Error
+	return totals, err
+}
+
+// DeleteByID menghapus transaksi berdasarkan ID (soft delete).
+func (r *FinanceRepository) DeleteByID(id uint) error {
+	return r.db.Delete(&models.FinanceEntry{}, id).Error
+}
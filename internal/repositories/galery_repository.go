@@ -27,23 +27,31 @@ func (r *GaleryRepository) Update(galery *models.Galery) error {
 
 func (r *GaleryRepository) FindByID(id uint) (*models.Galery, error) {
 	var galery models.Galery
-	err := r.db.First(&galery, id).Error
+	err := r.db.Preload("Tags").First(&galery, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &galery, nil
 }
 
-func (r *GaleryRepository) GetAllGalerys(limit, offset int) ([]models.Galery, int64, error) {
+// GetAllGalerys mengambil galeri dengan pagination. Jika owners diisi, hanya galeri milik
+// organisasi dalam scope yang diambil.
+func (r *GaleryRepository) GetAllGalerys(tag string, owners *OwnerScope, limit, offset int) ([]models.Galery, int64, error) {
 	var galerys []models.Galery
 	var total int64
 
 	query := r.db.Model(&models.Galery{})
+	if tag != "" {
+		query = query.Scopes(taggedWith("galery_tags", tag))
+	}
+	if owners != nil {
+		query = query.Scopes(ownedBy(*owners))
+	}
 	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	if err := query.Limit(limit).Offset(offset).Find(&galerys).Error; err != nil {
+	if err := query.Preload("Tags").Limit(limit).Offset(offset).Find(&galerys).Error; err != nil {
 		return nil, 0, err
 	}
 
@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// InventoryRepository adalah repository untuk operasi terkait inventaris barang.
+type InventoryRepository struct {
+	db *gorm.DB
+}
+
+// NewInventoryRepository membuat instance inventory repository baru.
+func NewInventoryRepository() *InventoryRepository {
+	return &InventoryRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create membuat barang inventaris baru.
+func (r *InventoryRepository) Create(item *models.InventoryItem) error {
+	return r.db.Create(item).Error
+}
+
+// Update menyimpan perubahan pada barang inventaris.
+func (r *InventoryRepository) Update(item *models.InventoryItem) error {
+	return r.db.Save(item).Error
+}
+
+// FindByID mencari barang inventaris berdasarkan ID.
+func (r *InventoryRepository) FindByID(id uint) (*models.InventoryItem, error) {
+	var item models.InventoryItem
+	err := r.db.First(&item, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// FindByName mencari barang inventaris berdasarkan nama dalam huruf kecil.
+func (r *InventoryRepository) FindByName(name string) (*models.InventoryItem, error) {
+	var item models.InventoryItem
+	err := r.db.Where("LOWER(TRIM(name)) = ?", name).First(&item).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+// GetAll mengambil barang inventaris terurut berdasarkan nama, opsional difilter nama dan kondisi.
+func (r *InventoryRepository) GetAll(search, condition string) ([]models.InventoryItem, error) {
+	var items []models.InventoryItem
+	query := r.db.Model(&models.InventoryItem{})
+	if search != "" {
+		query = query.Where("name LIKE ?", "%"+search+"%")
+	}
+	if condition != "" {
+		query = query.Where("`condition` = ?", condition)
+	}
+	err := query.Order("name ASC").Find(&items).Error
+	return items, err
+}
+
+// DeleteByID menghapus barang inventaris berdasarkan ID (soft delete).
+func (r *InventoryRepository) DeleteByID(id uint) error {
+	return r.db.Delete(&models.InventoryItem{}, id).Error
+}
@@ -0,0 +1,89 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LetterRepository adalah repository untuk surat peminjaman.
+type LetterRepository struct {
+	db *gorm.DB
+}
+
+// NewLetterRepository membuat instance letter repository baru.
+func NewLetterRepository() *LetterRepository {
+	return &LetterRepository{
+		db: database.GetDB(),
+	}
+}
+
+// FindByRequestID mencari surat peminjaman untuk sebuah permintaan.
+func (r *LetterRepository) FindByRequestID(requestID uint) (*models.BorrowingLetter, error) {
+	var letter models.BorrowingLetter
+	err := r.db.Where("request_id = ?", requestID).First(&letter).Error
+	if err != nil {
+		return nil, err
+	}
+	return &letter, nil
+}
+
+// FindByVerificationCode mencari surat peminjaman beserta permintaannya berdasarkan kode verifikasi.
+func (r *LetterRepository) FindByVerificationCode(code string) (*models.BorrowingLetter, error) {
+	var letter models.BorrowingLetter
+	err := r.db.Preload("Request").Preload("Request.Item").
+		Where("verification_code = ?", code).First(&letter).Error
+	if err != nil {
+		return nil, err
+	}
+	return &letter, nil
+}
+
+// CreateNext menyimpan surat baru dengan nomor urut berikutnya pada tahun surat.
+// Baris nomor urut tahun tersebut dikunci agar dua surat tidak mendapat nomor urut yang sama;
+// formatNumber membentuk nomor surat dari nomor urut tersebut.
+func (r *LetterRepository) CreateNext(letter *models.BorrowingLetter, formatNumber func(sequence uint) string) error {
+	if err := r.ensureSequence(letter.Year); err != nil {
+		return err
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var counter models.LetterSequence
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("year = ?", letter.Year).First(&counter).Error
+		if err != nil {
+			return err
+		}
+		counter.LastSequence++
+		if err := tx.Model(&counter).Update("last_sequence", counter.LastSequence).Error; err != nil {
+			return err
+		}
+		letter.Sequence = counter.LastSequence
+		letter.Number = formatNumber(letter.Sequence)
+		return tx.Create(letter).Error
+	})
+}
+
+// ensureSequence membuat baris nomor urut untuk tahun tersebut jika belum ada, dimulai dari
+// nomor urut terbesar surat yang sudah terbit. Baris dibuat di luar transaksi penomoran agar
+// CreateNext selalu mengunci baris yang sudah ada, bukan rentang kosong.
+func (r *LetterRepository) ensureSequence(year int) error {
+	var existing int64
+	if err := r.db.Model(&models.LetterSequence{}).Where("year = ?", year).Count(&existing).Error; err != nil {
+		return err
+	}
+	if existing > 0 {
+		return nil
+	}
+
+	var last uint
+	err := r.db.Model(&models.BorrowingLetter{}).
+		Where("year = ?", year).
+		Select("COALESCE(MAX(sequence), 0)").Scan(&last).Error
+	if err != nil {
+		return err
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.LetterSequence{Year: year, LastSequence: last}).Error
+}
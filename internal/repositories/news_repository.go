@@ -4,10 +4,63 @@ import (
 	"bem_be/internal/database"
 	"bem_be/internal/models"
 	"errors"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+// NewsFilter berisi kriteria penyaringan daftar berita.
+type NewsFilter struct {
+	Status        string
+	Tag           string // slug tag
+	BEMID         *uint
+	AssociationID *uint
+	DepartmentID  *uint
+	// Owners membatasi daftar pada berita milik organisasi yang dikelola seorang pengurus
+	Owners *OwnerScope
+}
+
+// OwnerScope adalah BEM dan organisasi (himpunan, UKM, atau departemen) yang dikelola seorang pengurus.
+type OwnerScope struct {
+	BEMID           *uint
+	OrganizationIDs []uint
+}
+
+// ownedBy membatasi query pada konten milik salah satu pemilik dalam scope.
+func ownedBy(scope OwnerScope) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		// ID selalu dimulai dari 1 sehingga 0 tidak pernah cocok
+		var bemID uint
+		if scope.BEMID != nil {
+			bemID = *scope.BEMID
+		}
+		return db.Where("(bem_id = ? OR association_id IN ? OR department_id IN ?)", bemID, scope.OrganizationIDs, scope.OrganizationIDs)
+	}
+}
+
+// applyNewsFilter menerapkan filter berita ke query.
+func applyNewsFilter(query *gorm.DB, filter NewsFilter) *gorm.DB {
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Tag != "" {
+		query = query.Scopes(taggedWith("news_tags", filter.Tag))
+	}
+	if filter.BEMID != nil {
+		query = query.Where("bem_id = ?", *filter.BEMID)
+	}
+	if filter.AssociationID != nil {
+		query = query.Where("association_id = ?", *filter.AssociationID)
+	}
+	if filter.DepartmentID != nil {
+		query = query.Where("department_id = ?", *filter.DepartmentID)
+	}
+	if filter.Owners != nil {
+		query = query.Scopes(ownedBy(*filter.Owners))
+	}
+	return query
+}
+
 // NewsRepository adalah repository untuk operasi terkait berita.
 type NewsRepository struct {
 	db *gorm.DB
@@ -33,31 +86,112 @@ func (r *NewsRepository) Update(news *models.News) error {
 // FindByID mencari item berita berdasarkan ID (hanya yang aktif).
 func (r *NewsRepository) FindByID(id uint) (*models.News, error) {
 	var news models.News
-	err := r.db.First(&news, id).Error
+	err := r.db.Preload("Tags").First(&news, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &news, nil
 }
 
-// GetAllNews mengambil semua berita dengan pagination (hanya yang aktif).
-func (r *NewsRepository) GetAllNews(limit, offset int) ([]models.News, int64, error) {
+// GetAllNews mengambil semua berita sesuai filter dengan pagination (hanya yang aktif).
+func (r *NewsRepository) GetAllNews(filter NewsFilter, limit, offset int) ([]models.News, int64, error) {
 	var newsList []models.News
 	var total int64
 
+	query := applyNewsFilter(r.db.Model(&models.News{}), filter)
+
 	// Query untuk menghitung total data yang aktif
-	if err := r.db.Model(&models.News{}).Count(&total).Error; err != nil {
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
 	// Query untuk mengambil data dengan limit, offset, dan pengurutan
-	if err := r.db.Limit(limit).Offset(offset).Order("created_at DESC").Find(&newsList).Error; err != nil {
+	if err := query.Preload("Tags").Limit(limit).Offset(offset).Order("created_at DESC").Find(&newsList).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return newsList, total, nil
+}
+
+// publishedNews membatasi query pada berita yang sudah terbit. Berita terjadwal yang
+// waktu terbitnya sudah lewat ikut dihitung agar tidak menunggu publisher berikutnya.
+func publishedNews(now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(status = ? OR (status = ? AND publish_at <= ?))",
+			models.NewsStatusPublished, models.NewsStatusScheduled, now)
+	}
+}
+
+// GetPublishedNews mengambil berita yang sudah terbit sesuai filter, diurutkan dari waktu terbit terbaru.
+func (r *NewsRepository) GetPublishedNews(filter NewsFilter, limit, offset int) ([]models.News, int64, error) {
+	var newsList []models.News
+	var total int64
+
+	query := applyNewsFilter(r.db.Model(&models.News{}).Scopes(publishedNews(time.Now())), filter)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	// Berita lama yang belum memiliki waktu terbit memakai waktu pembuatannya
+	if err := query.Preload("Tags").Limit(limit).Offset(offset).Order("COALESCE(publish_at, created_at) DESC").Find(&newsList).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return newsList, total, nil
 }
 
+// FindPublishedByID mencari berita yang sudah terbit berdasarkan ID.
+func (r *NewsRepository) FindPublishedByID(id uint) (*models.News, error) {
+	var news models.News
+	err := r.db.Scopes(publishedNews(time.Now())).Preload("Tags").First(&news, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &news, nil
+}
+
+// FindPublishedBySlug mencari berita yang sudah terbit berdasarkan slug.
+func (r *NewsRepository) FindPublishedBySlug(slug string) (*models.News, error) {
+	var news models.News
+	err := r.db.Scopes(publishedNews(time.Now())).Preload("Tags").Where("slug = ?", slug).First(&news).Error
+	if err != nil {
+		return nil, err
+	}
+	return &news, nil
+}
+
+// SlugExists memeriksa apakah slug sudah dipakai berita lain, termasuk yang sudah dihapus.
+func (r *NewsRepository) SlugExists(slug string, excludeID uint) (bool, error) {
+	var count int64
+	query := r.db.Unscoped().Model(&models.News{}).Where("slug = ?", slug)
+	if excludeID > 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// FindWithoutSlug mengambil berita yang belum memiliki slug, termasuk yang sudah dihapus.
+func (r *NewsRepository) FindWithoutSlug() ([]models.News, error) {
+	var newsList []models.News
+	err := r.db.Unscoped().Where("slug IS NULL OR slug = ''").Order("id ASC").Find(&newsList).Error
+	return newsList, err
+}
+
+// UpdateSlug mengubah slug berita tanpa mengubah waktu pembaruan.
+func (r *NewsRepository) UpdateSlug(id uint, slug string) error {
+	return r.db.Unscoped().Model(&models.News{}).Where("id = ?", id).UpdateColumn("slug", slug).Error
+}
+
+// PublishDue menerbitkan berita terjadwal yang waktu terbitnya sudah lewat
+// dan mengembalikan jumlah berita yang diterbitkan.
+func (r *NewsRepository) PublishDue(now time.Time) (int64, error) {
+	result := r.db.Model(&models.News{}).
+		Where("status = ? AND publish_at <= ?", models.NewsStatusScheduled, now).
+		Update("status", models.NewsStatusPublished)
+	return result.RowsAffected, result.Error
+}
+
 // DeleteByID menghapus item berita berdasarkan ID (soft delete).
 func (r *NewsRepository) DeleteByID(id uint) error {
 	return r.db.Delete(&models.News{}, id).Error
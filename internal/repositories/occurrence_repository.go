@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// OccurrenceRepository adalah repository untuk perubahan per pertemuan kegiatan berulang.
+type OccurrenceRepository struct {
+	db *gorm.DB
+}
+
+// NewOccurrenceRepository membuat instance occurrence repository baru.
+func NewOccurrenceRepository() *OccurrenceRepository {
+	return &OccurrenceRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Save membuat atau memperbarui perubahan sebuah pertemuan.
+func (r *OccurrenceRepository) Save(occurrence *models.ActivityOccurrence) error {
+	return r.db.Save(occurrence).Error
+}
+
+// FindByActivityAndStart mengambil perubahan pertemuan berdasarkan waktu mulai aslinya.
+func (r *OccurrenceRepository) FindByActivityAndStart(activityID uint, occurrenceStart time.Time) (*models.ActivityOccurrence, error) {
+	var occurrence models.ActivityOccurrence
+	err := r.db.Where("activity_id = ? AND occurrence_start = ?", activityID, occurrenceStart).First(&occurrence).Error
+	if err != nil {
+		return nil, err
+	}
+	return &occurrence, nil
+}
+
+// GetByActivityIDs mengambil semua perubahan pertemuan untuk kegiatan-kegiatan tersebut.
+func (r *OccurrenceRepository) GetByActivityIDs(activityIDs []uint) ([]models.ActivityOccurrence, error) {
+	var occurrences []models.ActivityOccurrence
+	if len(activityIDs) == 0 {
+		return occurrences, nil
+	}
+	err := r.db.Where("activity_id IN ?", activityIDs).Find(&occurrences).Error
+	return occurrences, err
+}
+
+// DeleteByID menghapus perubahan pertemuan sehingga pertemuan kembali mengikuti rangkaiannya.
+func (r *OccurrenceRepository) DeleteByID(id uint) error {
+	return r.db.Delete(&models.ActivityOccurrence{}, id).Error
+}
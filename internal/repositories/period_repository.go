@@ -0,0 +1,73 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// PeriodRepository adalah repository untuk operasi terkait periode kepengurusan.
+type PeriodRepository struct {
+	db *gorm.DB
+}
+
+// NewPeriodRepository membuat instance period repository baru.
+func NewPeriodRepository() *PeriodRepository {
+	return &PeriodRepository{
+		db: database.GetDB(),
+	}
+}
+
+// FindCurrentByOrganizationID mencari periode kepengurusan terbaru milik organisasi.
+func (r *PeriodRepository) FindCurrentByOrganizationID(orgID int) (*models.Period, error) {
+	var period models.Period
+	err := r.db.Where("organization_id = ?", orgID).Order("id DESC").First(&period).Error
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+// FindByID mencari periode kepengurusan berdasarkan ID.
+func (r *PeriodRepository) FindByID(id uint) (*models.Period, error) {
+	var period models.Period
+	err := r.db.First(&period, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+// FindNextByOrganizationID mencari periode kepengurusan organisasi yang dibuat setelah periode tertentu.
+func (r *PeriodRepository) FindNextByOrganizationID(orgID int, afterID uint) (*models.Period, error) {
+	var period models.Period
+	err := r.db.Where("organization_id = ? AND id > ?", orgID, afterID).Order("id ASC").First(&period).Error
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+// FindCurrentByOfficer mencari periode kepengurusan terbaru tiap organisasi tempat user menjabat
+// sebagai ketua, wakil ketua, atau sekretaris. userID adalah user_id kampus (lihat AssignToPeriod).
+func (r *PeriodRepository) FindCurrentByOfficer(userID uint) ([]models.Period, error) {
+	return r.findCurrentByPosition("(leader_id = ? OR co_leader_id = ? OR secretary1_id = ? OR secretary2_id = ?)", userID, userID, userID, userID)
+}
+
+// FindCurrentByTreasurer mencari periode kepengurusan terbaru tiap organisasi tempat user menjabat
+// sebagai bendahara. userID adalah user_id kampus (lihat AssignToPeriod).
+func (r *PeriodRepository) FindCurrentByTreasurer(userID uint) ([]models.Period, error) {
+	return r.findCurrentByPosition("(treasurer1_id = ? OR treasurer2_id = ?)", userID, userID)
+}
+
+// findCurrentByPosition mencari periode kepengurusan terbaru tiap organisasi yang memenuhi kondisi jabatan.
+func (r *PeriodRepository) findCurrentByPosition(condition string, args ...interface{}) ([]models.Period, error) {
+	var periods []models.Period
+	current := r.db.Model(&models.Period{}).Select("MAX(id)").Group("organization_id")
+	err := r.db.Preload("Organization").
+		Where("id IN (?)", current).
+		Where(condition, args...).
+		Find(&periods).Error
+	return periods, err
+}
@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ProposalRepository adalah repository untuk operasi terkait proposal kegiatan.
+type ProposalRepository struct {
+	db *gorm.DB
+}
+
+// NewProposalRepository membuat instance proposal repository baru.
+func NewProposalRepository() *ProposalRepository {
+	return &ProposalRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Transaction menjalankan fn dengan repository yang terikat pada satu transaksi database.
+func (r *ProposalRepository) Transaction(fn func(tx *ProposalRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&ProposalRepository{db: tx})
+	})
+}
+
+// LockActivity mengunci data kegiatan agar pengajuan proposal bersamaan untuk kegiatan
+// yang sama diperiksa satu per satu.
+func (r *ProposalRepository) LockActivity(activityID uint) error {
+	var activity models.Activity
+	return r.db.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&activity, activityID).Error
+}
+
+// Create membuat proposal baru.
+func (r *ProposalRepository) Create(proposal *models.Proposal) error {
+	return r.db.Create(proposal).Error
+}
+
+// FindByID mencari proposal beserta riwayat tinjauannya berdasarkan ID.
+func (r *ProposalRepository) FindByID(id uint) (*models.Proposal, error) {
+	var proposal models.Proposal
+	err := r.db.
+		Preload("Activity").
+		Preload("Reviews", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC")
+		}).
+		First(&proposal, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &proposal, nil
+}
+
+// GetByActivityID mengambil semua proposal milik sebuah kegiatan, terbaru lebih dulu.
+func (r *ProposalRepository) GetByActivityID(activityID uint) ([]models.Proposal, error) {
+	var proposals []models.Proposal
+	err := r.db.Where("activity_id = ?", activityID).Order("created_at DESC").Find(&proposals).Error
+	return proposals, err
+}
+
+// CountActiveByActivityID menghitung proposal kegiatan yang belum ditolak.
+func (r *ProposalRepository) CountActiveByActivityID(activityID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Proposal{}).
+		Where("activity_id = ? AND status <> ?", activityID, models.ProposalStatusRejected).
+		Count(&count).Error
+	return count, err
+}
+
+// SaveTransition menyimpan status baru proposal dan catatan tinjauannya dalam satu transaksi.
+// Status hanya diubah jika proposal masih berstatus review.FromStatus; nilai pertama bernilai
+// false jika proposal sudah ditinjau oleh peninjau lain lebih dulu.
+func (r *ProposalRepository) SaveTransition(proposal *models.Proposal, review *models.ProposalReview) (bool, error) {
+	saved := false
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Proposal{}).
+			Where("id = ? AND status = ?", proposal.ID, review.FromStatus).
+			Update("status", proposal.Status)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		saved = true
+		return tx.Create(review).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return saved, nil
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// RegistrationRepository adalah repository untuk operasi terkait pendaftaran kegiatan.
+type RegistrationRepository struct {
+	db *gorm.DB
+}
+
+// NewRegistrationRepository membuat instance registration repository baru.
+func NewRegistrationRepository() *RegistrationRepository {
+	return &RegistrationRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Transaction menjalankan fn dengan repository yang terikat pada satu transaksi database.
+func (r *RegistrationRepository) Transaction(fn func(tx *RegistrationRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&RegistrationRepository{db: tx})
+	})
+}
+
+// LockActivity mengambil kegiatan dengan row lock agar kuota tidak terlampaui oleh pendaftaran bersamaan.
+func (r *RegistrationRepository) LockActivity(activityID uint) (*models.Activity, error) {
+	var activity models.Activity
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&activity, activityID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &activity, nil
+}
+
+// Save membuat atau memperbarui pendaftaran.
+func (r *RegistrationRepository) Save(registration *models.ActivityRegistration) error {
+	return r.db.Save(registration).Error
+}
+
+// FindByActivityAndStudent mencari pendaftaran mahasiswa pada sebuah kegiatan.
+func (r *RegistrationRepository) FindByActivityAndStudent(activityID, studentID uint) (*models.ActivityRegistration, error) {
+	var registration models.ActivityRegistration
+	err := r.db.Where("activity_id = ? AND student_id = ?", activityID, studentID).First(&registration).Error
+	if err != nil {
+		return nil, err
+	}
+	return &registration, nil
+}
+
+// CountByStatus menghitung pendaftaran kegiatan dengan status tertentu.
+func (r *RegistrationRepository) CountByStatus(activityID uint, status string) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.ActivityRegistration{}).
+		Where("activity_id = ? AND status = ?", activityID, status).
+		Count(&count).Error
+	return count, err
+}
+
+// FirstWaitlisted mengambil pendaftar daftar tunggu paling awal.
+func (r *RegistrationRepository) FirstWaitlisted(activityID uint) (*models.ActivityRegistration, error) {
+	var registration models.ActivityRegistration
+	err := r.db.Where("activity_id = ? AND status = ?", activityID, models.RegistrationWaitlisted).
+		Order("registered_at ASC").Order("id ASC").
+		First(&registration).Error
+	if err != nil {
+		return nil, err
+	}
+	return &registration, nil
+}
+
+// GetByActivityID mengambil pendaftar kegiatan beserta data mahasiswanya, opsional per status.
+func (r *RegistrationRepository) GetByActivityID(activityID uint, status string) ([]models.ActivityRegistration, error) {
+	var registrations []models.ActivityRegistration
+	query := r.db.Preload("Student").Where("activity_id = ?", activityID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	err := query.Order("registered_at ASC").Order("id ASC").Find(&registrations).Error
+	return registrations, err
+}
+
+// GetByStudentID mengambil semua pendaftaran milik seorang mahasiswa beserta kegiatannya.
+func (r *RegistrationRepository) GetByStudentID(studentID uint) ([]models.ActivityRegistration, error) {
+	var registrations []models.ActivityRegistration
+	err := r.db.Preload("Activity").Where("student_id = ?", studentID).
+		Order("registered_at DESC").Find(&registrations).Error
+	return registrations, err
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ReportRepository adalah repository untuk operasi terkait laporan pertanggungjawaban (LPJ).
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository membuat instance report repository baru.
+func NewReportRepository() *ReportRepository {
+	return &ReportRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create membuat laporan baru.
+func (r *ReportRepository) Create(report *models.Report) error {
+	return r.db.Create(report).Error
+}
+
+// Update menyimpan perubahan pada laporan yang ada.
+func (r *ReportRepository) Update(report *models.Report) error {
+	return r.db.Save(report).Error
+}
+
+// FindByActivityID mencari laporan milik sebuah kegiatan.
+func (r *ReportRepository) FindByActivityID(activityID uint) (*models.Report, error) {
+	var report models.Report
+	err := r.db.Where("activity_id = ?", activityID).First(&report).Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// FindOverdueActivities mengambil kegiatan (atau rangkaian kegiatan berulang) yang selesai sebelum cutoff
+// namun belum memiliki laporan.
+func (r *ReportRepository) FindOverdueActivities(cutoff time.Time, filter ActivityFilter) ([]models.Activity, error) {
+	var activities []models.Activity
+
+	query := applyActivityFilter(r.db.Model(&models.Activity{}), filter).
+		Where("COALESCE(series_end_date, end_date) < ?", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM reports WHERE reports.activity_id = activities.id AND reports.deleted_at IS NULL)")
+
+	err := query.Order("COALESCE(series_end_date, end_date) ASC").Find(&activities).Error
+	return activities, err
+}
@@ -5,6 +5,7 @@ import (
 	"bem_be/internal/models"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type RequestRepository struct {
@@ -17,6 +18,34 @@ func NewRequestRepository() *RequestRepository {
 	}
 }
 
+// Transaction menjalankan fn dengan repository yang terikat pada satu transaksi database.
+func (r *RequestRepository) Transaction(fn func(tx *RequestRepository) error) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&RequestRepository{db: tx})
+	})
+}
+
+// LockByID mengambil permintaan dengan row lock agar statusnya tidak diubah bersamaan.
+func (r *RequestRepository) LockByID(id uint) (*models.Request, error) {
+	var request models.Request
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&request, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// LockItem mengambil barang inventaris dengan row lock agar persetujuan bersamaan
+// atas barang yang sama tidak melebihi stok.
+func (r *RequestRepository) LockItem(itemID uint) (*models.InventoryItem, error) {
+	var item models.InventoryItem
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).First(&item, itemID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
 func (r *RequestRepository) Create(request *models.Request) error {
 	return r.db.Create(request).Error
 }
@@ -25,9 +54,16 @@ func (r *RequestRepository) Update(request *models.Request) error {
 	return r.db.Save(request).Error
 }
 
+func (r *RequestRepository) UpdateStatus(request *models.Request) error {
+	return r.db.Model(request).
+		Select("status", "status_reason", "approver_id", "decided_at", "cancelled_by_id", "cancelled_at",
+			"handed_over_by_id", "handed_over_at", "hand_over_note", "returned_by_id", "returned_at", "return_condition", "updated_at").
+		Updates(request).Error
+}
+
 func (r *RequestRepository) FindByID(id uint) (*models.Request, error) {
 	var request models.Request
-	err := r.db.First(&request, id).Error
+	err := r.db.Preload("Item").First(&request, id).Error
 	if err != nil {
 		return nil, err
 	}
@@ -43,13 +79,57 @@ func (r *RequestRepository) GetAllRequests(limit, offset int) ([]models.Request,
 		return nil, 0, err
 	}
 
-	if err := query.Limit(limit).Offset(offset).Find(&requests).Error; err != nil {
+	if err := query.Preload("Item").Limit(limit).Offset(offset).Find(&requests).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return requests, total, nil
+}
+
+// GetByOrganizationID mengambil permintaan peminjaman sebuah organisasi dengan pagination,
+// terbaru lebih dulu, opsional difilter status.
+func (r *RequestRepository) GetByOrganizationID(organizationID int, status string, limit, offset int) ([]models.Request, int64, error) {
+	var requests []models.Request
+	var total int64
+
+	query := r.db.Model(&models.Request{}).Where("organization_id = ?", organizationID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("Item").Order("created_at DESC").Limit(limit).Offset(offset).Find(&requests).Error
+	if err != nil {
 		return nil, 0, err
 	}
 
 	return requests, total, nil
 }
 
+// GetActiveByItemIDs mengambil permintaan yang sudah disetujui atau barangnya sedang dipinjam
+// untuk barang-barang inventaris. Jika itemIDs kosong, semua permintaan aktif yang merujuk
+// barang inventaris diambil.
+func (r *RequestRepository) GetActiveByItemIDs(itemIDs []uint) ([]models.Request, error) {
+	var requests []models.Request
+	query := r.db.Where("status IN ? AND item_id IS NOT NULL",
+		[]string{models.RequestStatusApproved, models.RequestStatusHandedOver})
+	if len(itemIDs) > 0 {
+		query = query.Where("item_id IN ?", itemIDs)
+	}
+	err := query.Find(&requests).Error
+	return requests, err
+}
+
+// GetHandedOver mengambil permintaan yang barangnya sedang dipinjam dan belum dikembalikan.
+func (r *RequestRepository) GetHandedOver() ([]models.Request, error) {
+	var requests []models.Request
+	err := r.db.Preload("Item").Where("status = ?", models.RequestStatusHandedOver).
+		Order("return_plan ASC").Find(&requests).Error
+	return requests, err
+}
+
 func (r *RequestRepository) DeleteByID(id uint) error {
 	return r.db.Delete(&models.Request{}, id).Error
 }
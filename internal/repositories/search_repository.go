@@ -0,0 +1,184 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/search"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// searchSource menjelaskan cara membaca satu jenis konten untuk pencarian.
+type searchSource struct {
+	docType string
+	// fullText adalah tabel konten beserta FULLTEXT index yang dipakai backend database
+	fullText database.FullTextIndex
+	title    string
+	body     string
+	slug     string
+	date     string
+	// scope membatasi baris yang boleh muncul di hasil pencarian
+	scope func(*gorm.DB) *gorm.DB
+}
+
+// searchSources adalah semua konten yang dapat dicari. Setiap scope mengikuti endpoint
+// publik jenis konten tersebut agar pencarian tidak membuka konten yang tersembunyi.
+var searchSources = []searchSource{
+	{
+		docType: search.TypeNews, fullText: database.NewsSearchIndex,
+		title: "title", body: "content", slug: "slug", date: "COALESCE(publish_at, created_at)",
+		scope: func(db *gorm.DB) *gorm.DB { return publishedNews(time.Now())(db) },
+	},
+	{
+		docType: search.TypeAnnouncement, fullText: database.AnnouncementSearchIndex,
+		title: "title", body: "content", slug: "slug", date: "created_at",
+		scope: func(db *gorm.DB) *gorm.DB { return activeAnnouncements(time.Now())(db) },
+	},
+	{
+		docType: search.TypeGallery, fullText: database.GallerySearchIndex,
+		title: "title", body: "content", slug: "''", date: "created_at",
+	},
+	{
+		docType: search.TypeOrganization, fullText: database.OrganizationSearchIndex,
+		title: "name", body: "short_name", slug: "''", date: "created_at",
+		scope: guestOrganizations,
+	},
+	{
+		docType: search.TypeActivity, fullText: database.ActivitySearchIndex,
+		title: "title", body: "CONCAT_WS(' ', description, location)", slug: "''", date: "start_date",
+	},
+}
+
+// guestOrganizations membatasi organisasi pada UKM, departemen, dan himpunan yang
+// ditampilkan di halaman tamu (lihat GetAll*Guest).
+func guestOrganizations(db *gorm.DB) *gorm.DB {
+	return db.Where("category_id IN ?", []int{1, 2, 3})
+}
+
+// searchRow adalah satu baris hasil pencarian dari database.
+type searchRow struct {
+	ID    uint
+	Title string
+	Body  string
+	Slug  string
+	Date  time.Time
+	Score float64
+}
+
+// SearchRepository adalah repository pencarian konten. Repository ini juga menjadi
+// indeks pencarian berbasis FULLTEXT index MySQL untuk production.
+type SearchRepository struct {
+	db *gorm.DB
+}
+
+// NewSearchRepository membuat instance search repository baru.
+func NewSearchRepository() *SearchRepository {
+	return &SearchRepository{
+		db: database.GetDB(),
+	}
+}
+
+// HasFullTextIndexes memeriksa apakah FULLTEXT index pencarian sudah dibuat saat migrasi.
+func (r *SearchRepository) HasFullTextIndexes() bool {
+	return database.HasFullTextIndexes(r.db)
+}
+
+// query membuat query dasar untuk satu jenis konten, tanpa data yang sudah dihapus.
+func (r *SearchRepository) query(source searchSource) *gorm.DB {
+	query := r.db.Table(source.fullText.Table).Where("deleted_at IS NULL")
+	if source.scope != nil {
+		query = source.scope(query)
+	}
+	return query
+}
+
+// selectColumns adalah kolom yang dibaca untuk membangun dokumen pencarian.
+func (source searchSource) selectColumns() string {
+	return fmt.Sprintf("id, %s AS title, %s AS body, %s AS slug, %s AS date",
+		source.title, source.body, source.slug, source.date)
+}
+
+// LoadDocuments membaca semua konten yang dapat dicari, digunakan oleh indeks di memori.
+func (r *SearchRepository) LoadDocuments() ([]search.Document, error) {
+	var docs []search.Document
+	for _, source := range searchSources {
+		var rows []searchRow
+		if err := r.query(source).Select(source.selectColumns()).Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			docs = append(docs, row.document(source.docType))
+		}
+	}
+	return docs, nil
+}
+
+// document mengubah baris hasil query menjadi dokumen pencarian.
+func (row searchRow) document(docType string) search.Document {
+	return search.Document{Type: docType, ID: row.ID, Title: row.Title, Body: row.Body, Slug: row.Slug, Date: row.Date}
+}
+
+// booleanQuery mengubah istilah pencarian menjadi query BOOLEAN MODE MySQL.
+// Setiap istilah dicocokkan sebagai awalan kata dan cukup salah satunya yang cocok.
+func booleanQuery(terms []string) string {
+	parts := make([]string, 0, len(terms))
+	for _, term := range terms {
+		parts = append(parts, term+"*")
+	}
+	return strings.Join(parts, " ")
+}
+
+// Search mencari konten dengan FULLTEXT index dan mengurutkannya berdasarkan relevansi.
+// Skor relevansi MySQL dilipatgandakan jika judul memuat istilah pencarian.
+func (r *SearchRepository) Search(query search.Query) (search.Result, error) {
+	terms := search.Terms(query.Text)
+	if len(terms) == 0 {
+		return search.Result{Hits: []search.Hit{}}, nil
+	}
+
+	allowed := search.TypeFilter(query.Types)
+	against := booleanQuery(terms)
+	var candidates []search.Candidate
+	var total int64
+	for _, source := range searchSources {
+		if allowed != nil && !allowed[source.docType] {
+			continue
+		}
+
+		match := fmt.Sprintf("MATCH(%s) AGAINST (? IN BOOLEAN MODE)", strings.Join(source.fullText.Columns, ", "))
+		// Setiap jenis konten hanya membaca MaxResults baris teratas, jadi jumlah kecocokan dihitung terpisah
+		var count int64
+		if err := r.query(source).Where(match, against).Count(&count).Error; err != nil {
+			return search.Result{}, err
+		}
+		total += count
+
+		var rows []searchRow
+		err := r.query(source).
+			Select(source.selectColumns()+", "+match+" AS score", against).
+			Where(match, against).
+			Order("score DESC").Limit(search.MaxResults).
+			Scan(&rows).Error
+		if err != nil {
+			return search.Result{}, err
+		}
+
+		for _, row := range rows {
+			doc := row.document(source.docType)
+			score := row.Score
+			for _, token := range search.Tokenize(doc.Title) {
+				if search.MatchesTerm(token, terms) {
+					score *= 2
+					break
+				}
+			}
+			candidates = append(candidates, search.Candidate{Doc: doc, Score: score})
+		}
+	}
+	result := search.Rank(candidates, terms, query)
+	result.Total = int(total)
+	result.Capped = result.Total > search.MaxResults
+	return result, nil
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// SlugRepository adalah repository untuk slug lama yang dialihkan ke slug baru.
+type SlugRepository struct {
+	db *gorm.DB
+}
+
+// NewSlugRepository membuat instance slug repository baru.
+func NewSlugRepository() *SlugRepository {
+	return &SlugRepository{
+		db: database.GetDB(),
+	}
+}
+
+// FindRedirect mencari pengalihan slug lama. Mengembalikan nil jika tidak ada.
+func (r *SlugRepository) FindRedirect(entityType, slug string) (*models.SlugRedirect, error) {
+	var redirect models.SlugRedirect
+	err := r.db.Where("entity_type = ? AND slug = ?", entityType, slug).First(&redirect).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &redirect, nil
+}
+
+// SaveRedirect menyimpan pengalihan slug lama ke sebuah item.
+// Jika slug lama sudah tercatat, pengalihannya diarahkan ulang ke item tersebut.
+func (r *SlugRepository) SaveRedirect(entityType, slug string, entityID uint) error {
+	existing, err := r.FindRedirect(entityType, slug)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return r.db.Model(existing).Update("entity_id", entityID).Error
+	}
+	return r.db.Create(&models.SlugRedirect{EntityType: entityType, Slug: slug, EntityID: entityID}).Error
+}
+
+// DeleteRedirect menghapus pengalihan slug, misalnya saat slug tersebut kembali dipakai oleh itemnya.
+func (r *SlugRepository) DeleteRedirect(entityType, slug string) error {
+	return r.db.Where("entity_type = ? AND slug = ?", entityType, slug).Delete(&models.SlugRedirect{}).Error
+}
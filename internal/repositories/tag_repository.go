@@ -0,0 +1,131 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// tagJoinTables adalah tabel relasi tag beserta kolom ID kontennya.
+var tagJoinTables = map[string]string{
+	"news_tags":     "news_id",
+	"galery_tags":   "galery_id",
+	"activity_tags": "activity_id",
+}
+
+// taggedWith membatasi query pada konten yang memiliki tag dengan slug tersebut.
+func taggedWith(joinTable, slug string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		subQuery := db.Session(&gorm.Session{NewDB: true}).Table(joinTable).
+			Select(joinTable+"."+tagJoinTables[joinTable]).
+			Joins("JOIN tags ON tags.id = "+joinTable+".tag_id").
+			Where("tags.slug = ?", slug)
+		return db.Where("id IN (?)", subQuery)
+	}
+}
+
+// TagWithUsage adalah tag beserta jumlah konten yang memakainya.
+type TagWithUsage struct {
+	models.Tag
+	NewsCount     int64 `json:"news_count"`
+	GaleryCount   int64 `json:"galery_count"`
+	ActivityCount int64 `json:"activity_count"`
+}
+
+// TagRepository adalah repository untuk operasi terkait tag.
+type TagRepository struct {
+	db *gorm.DB
+}
+
+// NewTagRepository membuat instance tag repository baru.
+func NewTagRepository() *TagRepository {
+	return &TagRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create membuat tag baru.
+func (r *TagRepository) Create(tag *models.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+// Update menyimpan perubahan nama dan slug tag.
+func (r *TagRepository) Update(tag *models.Tag) error {
+	return r.db.Save(tag).Error
+}
+
+// FindByID mencari tag berdasarkan ID.
+func (r *TagRepository) FindByID(id uint) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.First(&tag, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// FindBySlug mencari tag berdasarkan slug. Mengembalikan nil jika tidak ada.
+func (r *TagRepository) FindBySlug(slug string) (*models.Tag, error) {
+	var tag models.Tag
+	err := r.db.Where("slug = ?", slug).First(&tag).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// GetAllWithUsage mengambil semua tag beserta jumlah pemakaiannya, diurutkan berdasarkan nama.
+func (r *TagRepository) GetAllWithUsage(search string) ([]TagWithUsage, error) {
+	var tags []TagWithUsage
+	query := r.db.Model(&models.Tag{}).Select(
+		"tags.*, " +
+			"(SELECT COUNT(*) FROM news_tags WHERE news_tags.tag_id = tags.id) AS news_count, " +
+			"(SELECT COUNT(*) FROM galery_tags WHERE galery_tags.tag_id = tags.id) AS galery_count, " +
+			"(SELECT COUNT(*) FROM activity_tags WHERE activity_tags.tag_id = tags.id) AS activity_count")
+	if search != "" {
+		query = query.Where("tags.name LIKE ?", "%"+search+"%")
+	}
+	err := query.Order("tags.name ASC").Scan(&tags).Error
+	return tags, err
+}
+
+// ReplaceTags mengganti seluruh tag milik sebuah konten (berita, galeri, atau kegiatan).
+func (r *TagRepository) ReplaceTags(owner interface{}, tags []models.Tag) error {
+	return r.db.Model(owner).Association("Tags").Replace(tags)
+}
+
+// DeleteByID menghapus tag beserta seluruh relasinya dengan konten.
+func (r *TagRepository) DeleteByID(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for joinTable := range tagJoinTables {
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE tag_id = ?", joinTable), id).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.Tag{}, id).Error
+	})
+}
+
+// Merge memindahkan semua konten bertag source ke target lalu menghapus source.
+// Konten yang sudah memiliki kedua tag tidak digandakan.
+func (r *TagRepository) Merge(sourceID, targetID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for joinTable, column := range tagJoinTables {
+			insert := fmt.Sprintf("INSERT IGNORE INTO %s (%s, tag_id) SELECT %s, ? FROM %s WHERE tag_id = ?",
+				joinTable, column, column, joinTable)
+			if err := tx.Exec(insert, targetID, sourceID).Error; err != nil {
+				return err
+			}
+			if err := tx.Exec(fmt.Sprintf("DELETE FROM %s WHERE tag_id = ?", joinTable), sourceID).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Delete(&models.Tag{}, sourceID).Error
+	})
+}
@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"bem_be/internal/database"
+	"bem_be/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// VenueRepository adalah repository untuk operasi terkait lokasi kegiatan.
+type VenueRepository struct {
+	db *gorm.DB
+}
+
+// NewVenueRepository membuat instance venue repository baru.
+func NewVenueRepository() *VenueRepository {
+	return &VenueRepository{
+		db: database.GetDB(),
+	}
+}
+
+// Create membuat lokasi baru.
+func (r *VenueRepository) Create(venue *models.Venue) error {
+	return r.db.Create(venue).Error
+}
+
+// Update menyimpan perubahan pada lokasi yang ada.
+func (r *VenueRepository) Update(venue *models.Venue) error {
+	return r.db.Save(venue).Error
+}
+
+// FindByID mencari lokasi berdasarkan ID.
+func (r *VenueRepository) FindByID(id uint) (*models.Venue, error) {
+	var venue models.Venue
+	err := r.db.First(&venue, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
+
+// FindByName mencari lokasi berdasarkan nama yang sudah dinormalisasi.
+func (r *VenueRepository) FindByName(name string) (*models.Venue, error) {
+	var venue models.Venue
+	err := r.db.Where("LOWER(TRIM(name)) = ?", name).First(&venue).Error
+	if err != nil {
+		return nil, err
+	}
+	return &venue, nil
+}
+
+// GetAll mengambil semua lokasi terurut berdasarkan nama.
+func (r *VenueRepository) GetAll() ([]models.Venue, error) {
+	var venues []models.Venue
+	err := r.db.Order("name ASC").Find(&venues).Error
+	return venues, err
+}
+
+// DeleteByID menghapus lokasi berdasarkan ID (soft delete).
+func (r *VenueRepository) DeleteByID(id uint) error {
+	return r.db.Delete(&models.Venue{}, id).Error
+}
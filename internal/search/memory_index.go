@@ -0,0 +1,144 @@
+package search
+
+import (
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// BM25 parameters
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+	// titleWeight counts a term in the title as this many occurrences in the body
+	titleWeight = 3
+	// prefixWeight scales matches where a word only starts with the query term
+	prefixWeight = 0.5
+)
+
+// Loader returns every document that should be searchable
+type Loader func() ([]Document, error)
+
+// indexedDocument is a document with its term frequencies
+type indexedDocument struct {
+	doc Document
+	// tf holds the weighted frequency of each term, title occurrences included
+	tf     map[string]float64
+	length float64
+}
+
+// MemoryIndex is an in-process inverted index ranked with BM25. It reloads
+// all documents from its loader once they are older than the refresh
+// interval, which keeps it simple enough for development and small sites.
+type MemoryIndex struct {
+	loader  Loader
+	refresh time.Duration
+
+	mu        sync.RWMutex
+	docs      []indexedDocument
+	postings  map[string][]int
+	avgLength float64
+	loadedAt  time.Time
+}
+
+// NewMemoryIndex creates an index that loads its documents lazily on the first search
+func NewMemoryIndex(loader Loader, refresh time.Duration) *MemoryIndex {
+	return &MemoryIndex{loader: loader, refresh: refresh}
+}
+
+// Rebuild replaces the indexed content with docs
+func (m *MemoryIndex) Rebuild(docs []Document) {
+	indexed := make([]indexedDocument, 0, len(docs))
+	postings := make(map[string][]int)
+	var totalLength float64
+
+	for _, doc := range docs {
+		entry := indexedDocument{doc: doc, tf: make(map[string]float64)}
+		for _, token := range Tokenize(doc.Title) {
+			entry.tf[token] += titleWeight
+			entry.length += titleWeight
+		}
+		for _, token := range Tokenize(doc.Body) {
+			entry.tf[token]++
+			entry.length++
+		}
+		for token := range entry.tf {
+			postings[token] = append(postings[token], len(indexed))
+		}
+		totalLength += entry.length
+		indexed = append(indexed, entry)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.docs = indexed
+	m.postings = postings
+	m.avgLength = 0
+	if len(indexed) > 0 {
+		m.avgLength = totalLength / float64(len(indexed))
+	}
+	m.loadedAt = time.Now()
+}
+
+// ensureFresh reloads the documents when the index is empty or stale
+func (m *MemoryIndex) ensureFresh() error {
+	m.mu.RLock()
+	fresh := !m.loadedAt.IsZero() && time.Since(m.loadedAt) < m.refresh
+	m.mu.RUnlock()
+	if fresh {
+		return nil
+	}
+
+	docs, err := m.loader()
+	if err != nil {
+		return err
+	}
+	m.Rebuild(docs)
+	return nil
+}
+
+// Search ranks documents matching any of the query terms
+func (m *MemoryIndex) Search(query Query) (Result, error) {
+	terms := Terms(query.Text)
+	if len(terms) == 0 {
+		return Result{Hits: []Hit{}}, nil
+	}
+	if err := m.ensureFresh(); err != nil {
+		return Result{}, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	allowed := TypeFilter(query.Types)
+	scores := make(map[int]float64)
+	total := float64(len(m.docs))
+	for _, term := range terms {
+		for token, docIDs := range m.postings {
+			weight := 1.0
+			if token != term {
+				if !strings.HasPrefix(token, term) {
+					continue
+				}
+				weight = prefixWeight
+			}
+			idf := math.Log(1 + (total-float64(len(docIDs))+0.5)/(float64(len(docIDs))+0.5))
+			for _, i := range docIDs {
+				entry := &m.docs[i]
+				if allowed != nil && !allowed[entry.doc.Type] {
+					continue
+				}
+				tf := entry.tf[token]
+				norm := tf + bm25K1*(1-bm25B+bm25B*entry.length/m.avgLength)
+				scores[i] += weight * idf * tf * (bm25K1 + 1) / norm
+			}
+		}
+	}
+
+	candidates := make([]Candidate, 0, len(scores))
+	for i, score := range scores {
+		candidates = append(candidates, Candidate{Doc: m.docs[i].doc, Score: score})
+	}
+	return Rank(candidates, terms, query), nil
+}
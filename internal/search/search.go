@@ -0,0 +1,141 @@
+// Package search provides site-wide search over public content. Backends
+// implement Index: MemoryIndex keeps an embedded inverted index for
+// development, while production uses database full-text indexes.
+package search
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Content types that can appear in search results
+const (
+	TypeNews         = "news"
+	TypeAnnouncement = "announcement"
+	TypeGallery      = "gallery"
+	TypeOrganization = "organization"
+	TypeActivity     = "activity"
+)
+
+// Types lists every searchable content type
+var Types = []string{TypeNews, TypeAnnouncement, TypeGallery, TypeOrganization, TypeActivity}
+
+// MaxResults caps how many ranked hits a single search considers, so deep
+// pages of very common terms stay cheap
+const MaxResults = 200
+
+// Document is a piece of content as seen by the search index
+type Document struct {
+	Type  string
+	ID    uint
+	Title string
+	// Body may contain HTML; it is stripped before indexing and highlighting
+	Body string
+	Slug string
+	Date time.Time
+}
+
+// Query describes a search request
+type Query struct {
+	Text string
+	// Types restricts the search to these content types; empty means all
+	Types  []string
+	Limit  int
+	Offset int
+}
+
+// Hit is a single ranked search result
+type Hit struct {
+	Type  string    `json:"type"`
+	ID    uint      `json:"id"`
+	Title string    `json:"title"`
+	Slug  string    `json:"slug,omitempty"`
+	Date  time.Time `json:"date"`
+	// Snippet is HTML-escaped text with matching words wrapped in <mark>
+	Snippet string  `json:"snippet"`
+	Score   float64 `json:"score"`
+}
+
+// Result is one page of hits together with the total number of matches.
+// Only the first MaxResults matches can be paged through; Capped reports
+// whether Total is larger than that.
+type Result struct {
+	Total  int   `json:"total"`
+	Capped bool  `json:"capped"`
+	Hits   []Hit `json:"hits"`
+}
+
+// Pageable returns how many of the matches can be paged through
+func (r Result) Pageable() int {
+	if r.Total > MaxResults {
+		return MaxResults
+	}
+	return r.Total
+}
+
+// Index is a searchable collection of site content
+type Index interface {
+	Search(query Query) (Result, error)
+}
+
+// Candidate is a matching document with its relevance score
+type Candidate struct {
+	Doc   Document
+	Score float64
+}
+
+// TypeFilter returns a lookup of the requested types, or nil when every type
+// is allowed
+func TypeFilter(types []string) map[string]bool {
+	if len(types) == 0 {
+		return nil
+	}
+	allowed := make(map[string]bool, len(types))
+	for _, t := range types {
+		allowed[t] = true
+	}
+	return allowed
+}
+
+// Rank orders candidates by descending score, newest first on ties, and turns
+// the requested page into hits with highlighted snippets. Total counts every
+// candidate, including those past MaxResults
+func Rank(candidates []Candidate, terms []string, query Query) Result {
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		if !candidates[i].Doc.Date.Equal(candidates[j].Doc.Date) {
+			return candidates[i].Doc.Date.After(candidates[j].Doc.Date)
+		}
+		if candidates[i].Doc.Type != candidates[j].Doc.Type {
+			return candidates[i].Doc.Type < candidates[j].Doc.Type
+		}
+		return candidates[i].Doc.ID < candidates[j].Doc.ID
+	})
+	result := Result{Total: len(candidates), Capped: len(candidates) > MaxResults, Hits: []Hit{}}
+	if result.Capped {
+		candidates = candidates[:MaxResults]
+	}
+	if query.Offset >= len(candidates) {
+		return result
+	}
+	end := len(candidates)
+	if query.Limit > 0 && query.Offset+query.Limit < end {
+		end = query.Offset + query.Limit
+	}
+	for _, candidate := range candidates[query.Offset:end] {
+		doc := candidate.Doc
+		result.Hits = append(result.Hits, Hit{
+			Type:    doc.Type,
+			ID:      doc.ID,
+			Title:   doc.Title,
+			Slug:    doc.Slug,
+			Date:    doc.Date,
+			Snippet: Highlight(doc.Body, terms, SnippetLength),
+			Score:   math.Round(candidate.Score*1000) / 1000,
+		})
+	}
+	return result
+}
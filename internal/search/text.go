@@ -0,0 +1,161 @@
+package search
+
+import (
+	"bem_be/internal/utils"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// SnippetLength is the maximum number of characters in a snippet
+const SnippetLength = 160
+
+// snippetContext is how many characters are kept before the first match
+const snippetContext = 50
+
+// stopWords are common Indonesian and English words that carry no meaning on
+// their own and are left out of queries
+var stopWords = map[string]bool{
+	"yang": true, "dan": true, "di": true, "ke": true, "dari": true, "untuk": true,
+	"dengan": true, "pada": true, "ini": true, "itu": true, "dalam": true, "atau": true,
+	"adalah": true, "akan": true, "oleh": true, "juga": true, "the": true, "of": true,
+	"and": true, "to": true, "in": true, "a": true, "an": true, "for": true, "on": true,
+}
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// PlainText strips HTML tags and entities and collapses whitespace
+func PlainText(text string) string {
+	text = html.UnescapeString(htmlTagPattern.ReplaceAllString(text, " "))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// isWordRune reports whether r is part of a word
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// normalizeWord lowercases a word and transliterates accented letters, so
+// "Séminar" and "seminar" are the same term
+func normalizeWord(word string) string {
+	return strings.ReplaceAll(utils.Slugify(word), "-", "")
+}
+
+// Tokenize splits text into normalized terms, keeping duplicates
+func Tokenize(text string) []string {
+	words := strings.FieldsFunc(PlainText(text), func(r rune) bool { return !isWordRune(r) })
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		if token := normalizeWord(word); token != "" {
+			tokens = append(tokens, token)
+		}
+	}
+	return tokens
+}
+
+// Terms returns the distinct meaningful terms of a query. Stop words are
+// dropped unless the query consists only of stop words.
+func Terms(query string) []string {
+	tokens := Tokenize(query)
+	seen := make(map[string]bool, len(tokens))
+	var terms, stops []string
+	for _, token := range tokens {
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+		if stopWords[token] {
+			stops = append(stops, token)
+			continue
+		}
+		terms = append(terms, token)
+	}
+	if len(terms) == 0 {
+		return stops
+	}
+	return terms
+}
+
+// MatchesTerm reports whether a normalized word matches one of the terms.
+// Words starting with a term match too, so "kegiatan" is found by "kegiat".
+func MatchesTerm(word string, terms []string) bool {
+	for _, term := range terms {
+		if strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+// segment is a run of either word or non-word characters
+type segment struct {
+	text  string
+	match bool
+}
+
+// splitSegments splits text into alternating word and separator segments and
+// marks the words that match the terms
+func splitSegments(text string, terms []string) []segment {
+	var segments []segment
+	start := 0
+	inWord := false
+	flush := func(end int) {
+		if end <= start {
+			return
+		}
+		part := text[start:end]
+		segments = append(segments, segment{text: part, match: inWord && MatchesTerm(normalizeWord(part), terms)})
+		start = end
+	}
+	for i, r := range text {
+		if word := isWordRune(r); word != inWord {
+			flush(i)
+			inWord = word
+		}
+	}
+	flush(len(text))
+	return segments
+}
+
+// Highlight returns an HTML-escaped excerpt of text of at most maxLen
+// characters around the first match, with matching words wrapped in <mark>
+func Highlight(text string, terms []string, maxLen int) string {
+	segments := splitSegments(PlainText(text), terms)
+
+	// Start a little before the first match so it is shown in context
+	first := 0
+	for i, seg := range segments {
+		if seg.match {
+			first = i
+			break
+		}
+	}
+	begin, context := first, 0
+	for begin > 0 && context+utf8.RuneCountInString(segments[begin-1].text) <= snippetContext {
+		begin--
+		context += utf8.RuneCountInString(segments[begin].text)
+	}
+
+	var b strings.Builder
+	if begin > 0 {
+		b.WriteString("…")
+	}
+	length := 0
+	for i := begin; i < len(segments); i++ {
+		seg := segments[i]
+		segLen := utf8.RuneCountInString(seg.text)
+		if length+segLen > maxLen && length > 0 {
+			b.WriteString("…")
+			break
+		}
+		length += segLen
+		if seg.match {
+			b.WriteString("<mark>" + html.EscapeString(seg.text) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(seg.text))
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
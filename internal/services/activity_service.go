@@ -0,0 +1,282 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"bem_be/internal/utils"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrActivityNotFound dikembalikan jika kegiatan tidak ditemukan
+	ErrActivityNotFound = errors.New("kegiatan tidak ditemukan")
+	// ErrVenueConflict dikembalikan jika jadwal bentrok di lokasi yang bersifat eksklusif
+	ErrVenueConflict = errors.New("jadwal kegiatan bentrok dengan kegiatan lain di lokasi yang sama")
+	// ErrOccurrenceHasData dikembalikan jika perubahan jadwal menghilangkan pertemuan yang sudah
+	// memiliki perubahan jadwal atau presensi
+	ErrOccurrenceHasData = errors.New("jadwal tidak dapat diubah karena pertemuan yang sudah memiliki perubahan atau presensi akan hilang")
+)
+
+// ActivityConflict adalah kegiatan lain yang memakai lokasi yang sama pada waktu yang beririsan.
+type ActivityConflict struct {
+	ActivityID uint      `json:"activity_id"`
+	Title      string    `json:"title"`
+	Location   string    `json:"location"`
+	StartDate  time.Time `json:"start_date"`
+	EndDate    time.Time `json:"end_date"`
+}
+
+// ActivityService adalah service untuk operasi kegiatan.
+type ActivityService struct {
+	repository     *repositories.ActivityRepository
+	venueRepo      *repositories.VenueRepository
+	occurrenceRepo *repositories.OccurrenceRepository
+	attendanceRepo *repositories.AttendanceRepository
+	registrations  *RegistrationService
+	tags           *TagService
+}
+
+// NewActivityService membuat service kegiatan baru.
+func NewActivityService(db *gorm.DB) *ActivityService {
+	return &ActivityService{
+		repository:     repositories.NewActivityRepository(),
+		venueRepo:      repositories.NewVenueRepository(),
+		occurrenceRepo: repositories.NewOccurrenceRepository(),
+		attendanceRepo: repositories.NewAttendanceRepository(),
+		registrations:  NewRegistrationService(db),
+		tags:           NewTagService(db),
+	}
+}
+
+// normalizeLocation merapikan spasi pada nama lokasi agar mudah dicocokkan.
+func normalizeLocation(location string) string {
+	return strings.Join(strings.Fields(location), " ")
+}
+
+// validateActivity memeriksa field wajib dan urutan tanggal kegiatan.
+func validateActivity(activity *models.Activity) error {
+	activity.Location = normalizeLocation(activity.Location)
+	if activity.Title == "" {
+		return errors.New("judul kegiatan tidak boleh kosong")
+	}
+	if activity.StartDate.IsZero() || activity.EndDate.IsZero() {
+		return errors.New("tanggal mulai dan selesai wajib diisi")
+	}
+	if activity.EndDate.Before(activity.StartDate) {
+		return errors.New("tanggal selesai tidak boleh sebelum tanggal mulai")
+	}
+	return validateRecurrence(activity)
+}
+
+// findConflicts mencari kegiatan lain di lokasi yang sama dengan waktu yang beririsan,
+// termasuk antar pertemuan kegiatan berulang.
+func (s *ActivityService) findConflicts(activity *models.Activity) ([]ActivityConflict, error) {
+	var overrides []models.ActivityOccurrence
+	if activity.ID != 0 {
+		var err error
+		overrides, err = s.occurrenceRepo.GetByActivityIDs([]uint{activity.ID})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.occurrenceConflicts(activity.ID, expandActivity(activity, overrides))
+}
+
+// occurrenceConflicts membandingkan pertemuan-pertemuan tersebut dengan pertemuan kegiatan lain
+// di lokasi yang sama. Jika lokasi terdaftar sebagai eksklusif, bentrok dikembalikan bersama ErrVenueConflict.
+func (s *ActivityService) occurrenceConflicts(activityID uint, occurrences []Occurrence) ([]ActivityConflict, error) {
+	conflicts := []ActivityConflict{}
+
+	byLocation := map[string][]Occurrence{}
+	for _, occurrence := range occurrences {
+		location := strings.ToLower(strings.TrimSpace(occurrence.Location))
+		if occurrence.Cancelled || location == "" {
+			continue
+		}
+		byLocation[location] = append(byLocation[location], occurrence)
+	}
+
+	exclusive := false
+	seen := map[string]bool{}
+	for location, own := range byLocation {
+		from, to := own[0].StartDate, own[0].EndDate
+		for _, occurrence := range own[1:] {
+			if occurrence.StartDate.Before(from) {
+				from = occurrence.StartDate
+			}
+			if occurrence.EndDate.After(to) {
+				to = occurrence.EndDate
+			}
+		}
+
+		others, err := s.repository.FindOverlapping(location, from, to, activityID)
+		if err != nil {
+			return nil, err
+		}
+		if len(others) == 0 {
+			continue
+		}
+
+		ids := make([]uint, 0, len(others))
+		for _, other := range others {
+			ids = append(ids, other.ID)
+		}
+		overrides, err := s.occurrenceRepo.GetByActivityIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+
+		found := false
+		for i := range others {
+			for _, theirs := range expandActivity(&others[i], overrides) {
+				if theirs.Cancelled || strings.ToLower(strings.TrimSpace(theirs.Location)) != location {
+					continue
+				}
+				for _, mine := range own {
+					if !mine.StartDate.Before(theirs.EndDate) || !theirs.StartDate.Before(mine.EndDate) {
+						continue
+					}
+					key := fmt.Sprintf("%d:%d", theirs.ActivityID, theirs.OccurrenceStart.Unix())
+					if !seen[key] {
+						seen[key] = true
+						found = true
+						conflicts = append(conflicts, ActivityConflict{
+							ActivityID: theirs.ActivityID,
+							Title:      theirs.Title,
+							Location:   theirs.Location,
+							StartDate:  theirs.StartDate,
+							EndDate:    theirs.EndDate,
+						})
+					}
+					break
+				}
+			}
+		}
+		if !found {
+			continue
+		}
+
+		venue, err := s.venueRepo.FindByName(location)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if venue != nil && venue.Exclusive {
+			exclusive = true
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].StartDate.Before(conflicts[j].StartDate)
+	})
+	if exclusive {
+		return conflicts, ErrVenueConflict
+	}
+	return conflicts, nil
+}
+
+// CreateActivity membuat kegiatan baru dan mengembalikan jadwal lain yang bentrok sebagai peringatan.
+func (s *ActivityService) CreateActivity(activity *models.Activity) ([]ActivityConflict, error) {
+	if err := validateActivity(activity); err != nil {
+		return nil, err
+	}
+	conflicts, err := s.findConflicts(activity)
+	if err != nil {
+		return conflicts, err
+	}
+	if activity.Tags, err = s.tags.ResolveTags(activity.Tags); err != nil {
+		return conflicts, err
+	}
+	return conflicts, s.repository.Create(activity)
+}
+
+// ensureOccurrencesKept memastikan setiap pertemuan yang sudah memiliki perubahan jadwal atau
+// presensi masih ada menurut aturan pengulangan yang baru. Keduanya diidentifikasi dengan
+// waktu mulai pertemuan, sehingga mengubah StartDate atau aturan pengulangan dapat memutus datanya.
+func (s *ActivityService) ensureOccurrencesKept(activity *models.Activity) error {
+	overrides, err := s.occurrenceRepo.GetByActivityIDs([]uint{activity.ID})
+	if err != nil {
+		return err
+	}
+	attended, err := s.attendanceRepo.GetOccurrenceStarts(activity.ID)
+	if err != nil {
+		return err
+	}
+
+	starts := map[int64]bool{}
+	for _, start := range seriesStarts(activity) {
+		starts[start.Unix()] = true
+	}
+	for _, override := range overrides {
+		if !starts[override.OccurrenceStart.Unix()] {
+			return ErrOccurrenceHasData
+		}
+	}
+	for _, start := range attended {
+		if !starts[start.Unix()] {
+			return ErrOccurrenceHasData
+		}
+	}
+	return nil
+}
+
+// UpdateActivity memperbarui kegiatan yang ada dan mengembalikan jadwal lain yang bentrok
+// sebagai peringatan. Jika kapasitas bertambah, pendaftar dari daftar tunggu otomatis dinaikkan.
+// Perubahan jadwal ditolak jika menghilangkan pertemuan yang sudah memiliki data.
+func (s *ActivityService) UpdateActivity(activity *models.Activity) ([]ActivityConflict, error) {
+	if err := validateActivity(activity); err != nil {
+		return nil, err
+	}
+	if err := s.ensureOccurrencesKept(activity); err != nil {
+		return nil, err
+	}
+	conflicts, err := s.findConflicts(activity)
+	if err != nil {
+		return conflicts, err
+	}
+	if activity.Tags, err = s.tags.ResolveTags(activity.Tags); err != nil {
+		return conflicts, err
+	}
+	if err := s.repository.Update(activity); err != nil {
+		return conflicts, err
+	}
+	if err := s.tags.ReplaceTags(activity, activity.Tags); err != nil {
+		return conflicts, err
+	}
+	return conflicts, s.registrations.FillFromWaitlist(activity.ID)
+}
+
+// GetActivityByID mendapatkan kegiatan berdasarkan ID.
+func (s *ActivityService) GetActivityByID(id uint) (*models.Activity, error) {
+	activity, err := s.repository.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("kegiatan tidak ditemukan")
+		}
+		return nil, err
+	}
+	return activity, nil
+}
+
+// GetAllActivities mendapatkan kegiatan sesuai filter dengan pagination.
+func (s *ActivityService) GetAllActivities(filter repositories.ActivityFilter, limit, offset int) ([]models.Activity, int64, error) {
+	filter.Tag = utils.Slugify(filter.Tag)
+	return s.repository.GetAllActivities(filter, limit, offset)
+}
+
+// DeleteActivity menghapus sebuah kegiatan.
+func (s *ActivityService) DeleteActivity(id uint) error {
+	_, err := s.repository.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("kegiatan yang akan dihapus tidak ditemukan")
+		}
+		return err
+	}
+	return s.repository.DeleteByID(id)
+}
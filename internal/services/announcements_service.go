@@ -11,6 +11,7 @@ import (
 // announcementService is a service for announcement operations
 type AnnouncementService struct {
 	repository *repositories.AnnouncementRepository
+	slugs      *SlugService
 	db *gorm.DB
 }
 
@@ -18,11 +19,29 @@ type AnnouncementService struct {
 func NewAnnouncementService(db *gorm.DB) *AnnouncementService {
     return &AnnouncementService{
         repository: repositories.NewAnnouncementRepository(),
+        slugs:      NewSlugService(),
     }
 }
 
-// Createannouncement creates a new announcement
-func (s *AnnouncementService) Createannouncement(announcement *models.Announcement) error {
+// announcementOwner returns the organization that owns the announcement
+func announcementOwner(announcement *models.Announcement) ContentOwner {
+	return ContentOwner{BEMID: announcement.BEMID, AssociationID: announcement.AssociationID, DepartmentID: announcement.DepartmentID}
+}
+
+// claimAnnouncementOwner checks that the author may publish on behalf of the owner,
+// filling in the officer's own organization when no owner was chosen
+func claimAnnouncementOwner(announcement *models.Announcement, author *Author) error {
+	owner, err := author.ClaimOwner(announcementOwner(announcement))
+	if err != nil {
+		return err
+	}
+	announcement.BEMID, announcement.AssociationID, announcement.DepartmentID = owner.BEMID, owner.AssociationID, owner.DepartmentID
+	return nil
+}
+
+// Createannouncement creates a new announcement. Organization officers may only
+// create announcements for their own organization
+func (s *AnnouncementService) Createannouncement(announcement *models.Announcement, author *Author) error {
 	// Check if code exists (including soft-deleted)
 	// exists, err := s.repository.CheckNameExists(announcement.Name, 0)
 	// if err != nil {
@@ -55,12 +74,20 @@ func (s *AnnouncementService) Createannouncement(announcement *models.Announceme
 	// 	return errors.New("kode gedung sudah digunakan")
 	// }
 
-	// Create announcement
-	return s.repository.Create(announcement)
+	if err := claimAnnouncementOwner(announcement, author); err != nil {
+		return err
+	}
+
+	// Create announcement, retrying with a new slug if a concurrent create took it first
+	return s.slugs.CreateWithSlug(models.SlugEntityAnnouncement, "pengumuman", announcement.Title, s.repository.SlugExists, func(slug string) error {
+		announcement.Slug = slug
+		return s.repository.Create(announcement)
+	})
 }
 
-// Updateannouncement updates an existing announcement
-func (s *AnnouncementService) Updateannouncement(announcement *models.Announcement) error {
+// Updateannouncement updates an existing announcement. Organization officers may only
+// edit announcements of their own organization
+func (s *AnnouncementService) Updateannouncement(announcement *models.Announcement, author *Author) error {
 	// Check if announcement exists
 	existingAnnouncement, err := s.repository.FindByID(announcement.ID)
 	if err != nil {
@@ -69,6 +96,22 @@ func (s *AnnouncementService) Updateannouncement(announcement *models.Announceme
 	if existingAnnouncement == nil {
 		return errors.New("himpunan tidak ditemukan")
 	}
+	if err := author.Authorize(announcementOwner(existingAnnouncement)); err != nil {
+		return err
+	}
+	if err := claimAnnouncementOwner(announcement, author); err != nil {
+		return err
+	}
+
+	// Regenerate the slug only when the title changes, keeping the old one as a redirect
+	announcement.Slug = existingAnnouncement.Slug
+	if existingAnnouncement.Title != announcement.Title || existingAnnouncement.Slug == "" {
+		slug, err := s.slugs.Assign(models.SlugEntityAnnouncement, "pengumuman", announcement.ID, announcement.Title, existingAnnouncement.Slug, s.repository.SlugExists)
+		if err != nil {
+			return err
+		}
+		announcement.Slug = slug
+	}
 
 	// Update announcement
 	return s.repository.Update(announcement)
@@ -79,13 +122,80 @@ func (s *AnnouncementService) GetannouncementByID(id uint) (*models.Announcement
 	return s.repository.FindByID(id)
 }
 
-// GetAllannouncements gets all announcements
-func (s *AnnouncementService) GetAllAnnouncements(limit, offset int) ([]models.Announcement, int64, error) {
-    return s.repository.GetAllAnnouncements(limit, offset)
+// GetManagedAnnouncementByID gets an announcement by ID if the author may manage it
+func (s *AnnouncementService) GetManagedAnnouncementByID(id uint, author *Author) (*models.Announcement, error) {
+	announcement, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := author.Authorize(announcementOwner(announcement)); err != nil {
+		return nil, err
+	}
+	return announcement, nil
+}
+
+// GetAnnouncementBySlug gets a currently active announcement by its current slug or by
+// a slug it had before its title was edited
+func (s *AnnouncementService) GetAnnouncementBySlug(slug string) (*models.Announcement, error) {
+	announcement, err := s.repository.FindActiveBySlug(slug)
+	if err == nil {
+		return announcement, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	id, err := s.slugs.Resolve(models.SlugEntityAnnouncement, slug)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		return nil, errors.New("pengumuman tidak ditemukan")
+	}
+	announcement, err = s.repository.FindActiveByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pengumuman tidak ditemukan")
+		}
+		return nil, err
+	}
+	return announcement, nil
 }
 
-// Deleteannouncement deletes a announcement
-func (s *AnnouncementService) DeleteAnnouncement(id uint) error {
+// BackfillSlugs generates slugs for announcements created before slugs existed
+func (s *AnnouncementService) BackfillSlugs() error {
+	announcements, err := s.repository.FindWithoutSlug()
+	if err != nil {
+		return err
+	}
+	for _, announcement := range announcements {
+		slug, err := s.slugs.Assign(models.SlugEntityAnnouncement, "pengumuman", announcement.ID, announcement.Title, "", s.repository.SlugExists)
+		if err != nil {
+			return err
+		}
+		if err := s.repository.UpdateSlug(announcement.ID, slug); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetManagedAnnouncements gets the announcements the author may manage. Admins get every
+// announcement, organization officers only those of their own organizations
+func (s *AnnouncementService) GetManagedAnnouncements(author *Author, limit, offset int) ([]models.Announcement, int64, error) {
+	var owners *repositories.OwnerScope
+	if !author.IsAdmin {
+		if !author.IsOfficer() {
+			return nil, 0, ErrNotOfficer
+		}
+		owners = &repositories.OwnerScope{BEMID: author.BEMID, OrganizationIDs: author.OrganizationIDs()}
+	}
+	return s.repository.GetAllAnnouncements(owners, limit, offset)
+}
+
+// Deleteannouncement deletes a announcement. Organization officers may only
+// delete announcements of their own organization
+func (s *AnnouncementService) DeleteAnnouncement(id uint, author *Author) error {
 	// Check if announcement exists
 	announcement, err := s.repository.FindByID(id)
 	if err != nil {
@@ -94,6 +204,9 @@ func (s *AnnouncementService) DeleteAnnouncement(id uint) error {
 	if announcement == nil {
 		return errors.New("gedung tidak ditemukan")
 	}
+	if err := author.Authorize(announcementOwner(announcement)); err != nil {
+		return err
+	}
 
 	// Delete announcement (soft delete)
 	return s.repository.DeleteByID(id)
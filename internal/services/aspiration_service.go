@@ -0,0 +1,547 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"bem_be/internal/utils"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// aspirationCategories adalah kategori aspirasi yang dapat dipilih mahasiswa.
+var aspirationCategories = map[string]bool{
+	models.AspirationCategoryAcademic: true,
+	models.AspirationCategoryFacility: true,
+	models.AspirationCategoryStudent:  true,
+	models.AspirationCategoryFinance:  true,
+	models.AspirationCategoryWelfare:  true,
+	models.AspirationCategoryOther:    true,
+}
+
+var (
+	// ErrInvalidAspirationTransition dikembalikan jika perubahan status aspirasi tidak diizinkan
+	ErrInvalidAspirationTransition = errors.New("perubahan status aspirasi tidak valid")
+	// ErrAspirationForbidden dikembalikan jika mahasiswa mengakses aspirasi milik orang lain
+	ErrAspirationForbidden = errors.New("anda tidak berhak mengakses aspirasi ini")
+	// ErrAlreadyVoted dikembalikan jika mahasiswa sudah mendukung aspirasi
+	ErrAlreadyVoted = errors.New("anda sudah mendukung aspirasi ini")
+	// ErrInvalidAspirationMerge dikembalikan jika aspirasi tidak dapat digabungkan
+	ErrInvalidAspirationMerge = errors.New("aspirasi tidak dapat digabungkan")
+	// ErrAspirationRateLimited dikembalikan jika mahasiswa terlalu sering mengirim aspirasi
+	ErrAspirationRateLimited = errors.New("anda terlalu sering mengirim aspirasi, silakan coba lagi nanti")
+	// ErrInvalidModerationAction dikembalikan jika aksi moderasi tidak dikenal
+	ErrInvalidModerationAction = errors.New("aksi moderasi tidak valid")
+)
+
+// moderationActions memetakan aksi moderasi admin ke status moderasi aspirasi.
+var moderationActions = map[string]string{
+	"approve": models.AspirationModerationApproved,
+	"hide":    models.AspirationModerationHidden,
+	"abusive": models.AspirationModerationAbusive,
+}
+
+// aspirationTransitions memetakan status asal ke status tujuan yang diizinkan.
+// Status resolved dan declined adalah status akhir.
+var aspirationTransitions = map[string][]string{
+	models.AspirationStatusReceived:  {models.AspirationStatusInReview, models.AspirationStatusDeclined},
+	models.AspirationStatusInReview:  {models.AspirationStatusForwarded, models.AspirationStatusResolved, models.AspirationStatusDeclined},
+	models.AspirationStatusForwarded: {models.AspirationStatusInReview, models.AspirationStatusResolved, models.AspirationStatusDeclined},
+}
+
+// AspirationVoteResult adalah jumlah dukungan aspirasi setelah mahasiswa mendukung atau
+// membatalkan dukungannya.
+type AspirationVoteResult struct {
+	ID        uint `json:"id"`
+	VoteCount int  `json:"vote_count"`
+	Voted     bool `json:"voted"`
+}
+
+// TrendingAspiration adalah aspirasi beserta skor trending-nya.
+type TrendingAspiration struct {
+	models.Aspiration
+	Score       float64 `json:"score"`
+	RecentVotes int     `json:"recent_votes"`
+}
+
+// AspirationService adalah service untuk aspirasi mahasiswa.
+type AspirationService struct {
+	repository     *repositories.AspirationRepository
+	studentRepo    *repositories.StudentRepository
+	orgRepo        *repositories.AssociationRepository
+	trendHalfLife  time.Duration
+	trendingWindow time.Duration
+	wordFilter     *utils.WordFilter
+	rateLimit      int
+	rateWindow     time.Duration
+	minInterval    time.Duration
+}
+
+// NewAspirationService membuat service aspirasi baru.
+func NewAspirationService(db *gorm.DB) *AspirationService {
+	return &AspirationService{
+		repository:     repositories.NewAspirationRepository(),
+		studentRepo:    repositories.NewStudentRepository(),
+		orgRepo:        repositories.NewAssociationRepository(),
+		trendHalfLife:  time.Duration(utils.GetEnvAsInt("ASPIRATION_TRENDING_HALF_LIFE_HOURS", 72)) * time.Hour,
+		trendingWindow: time.Duration(utils.GetEnvAsInt("ASPIRATION_TRENDING_WINDOW_DAYS", 30)) * 24 * time.Hour,
+		wordFilter:     utils.NewWordFilterFromEnv(),
+		rateLimit:      utils.GetEnvAsInt("ASPIRATION_RATE_LIMIT", 5),
+		rateWindow:     time.Duration(utils.GetEnvAsInt("ASPIRATION_RATE_WINDOW_MINUTES", 60)) * time.Minute,
+		minInterval:    time.Duration(utils.GetEnvAsInt("ASPIRATION_MIN_INTERVAL_SECONDS", 30)) * time.Second,
+	}
+}
+
+// hideAnonymousAuthor menyembunyikan identitas pengirim aspirasi anonim dari admin.
+func hideAnonymousAuthor(aspiration *models.Aspiration) {
+	if aspiration.IsAnonymous {
+		hideAuthor(aspiration)
+	}
+}
+
+// hideAuthor menghapus identitas pengirim aspirasi dari respons.
+func hideAuthor(aspiration *models.Aspiration) {
+	aspiration.UserID = 0
+	aspiration.User = nil
+	aspiration.StudentID = nil
+	aspiration.Student = nil
+}
+
+// checkRateLimit memastikan mahasiswa tidak melewati batas jumlah pengiriman dalam jendela waktu
+// dan jeda minimum antar pengiriman. Dipanggil di dalam transaksi yang mengunci data mahasiswa.
+func (s *AspirationService) checkRateLimit(tx *repositories.AspirationRepository, studentID uint) error {
+	now := time.Now()
+	since := now.Add(-s.rateWindow)
+	if interval := now.Add(-s.minInterval); interval.Before(since) {
+		since = interval
+	}
+	times, err := tx.GetSubmissionTimesSince(studentID, since)
+	if err != nil {
+		return err
+	}
+	if len(times) > 0 && now.Sub(times[0]) < s.minInterval {
+		return ErrAspirationRateLimited
+	}
+
+	inWindow := 0
+	for _, t := range times {
+		if !t.Before(now.Add(-s.rateWindow)) {
+			inWindow++
+		}
+	}
+	if s.rateLimit > 0 && inWindow >= s.rateLimit {
+		return ErrAspirationRateLimited
+	}
+	return nil
+}
+
+// SubmitAspiration menyimpan aspirasi baru dari mahasiswa pemilik token.
+// Pengirim tetap dicatat walaupun aspirasi dikirim secara anonim. Aspirasi yang mengandung
+// kata terlarang masuk antrean moderasi dan tidak tampil sebelum disetujui admin.
+func (s *AspirationService) SubmitAspiration(aspiration *models.Aspiration, userID uint) error {
+	aspiration.Title = strings.TrimSpace(aspiration.Title)
+	aspiration.Content = strings.TrimSpace(aspiration.Content)
+	aspiration.Category = strings.ToLower(strings.TrimSpace(aspiration.Category))
+
+	if aspiration.Content == "" {
+		return errors.New("isi aspirasi tidak boleh kosong")
+	}
+	if aspiration.Category == "" {
+		aspiration.Category = models.AspirationCategoryOther
+	}
+	if !aspirationCategories[aspiration.Category] {
+		return errors.New("kategori aspirasi tidak valid")
+	}
+
+	if aspiration.OrganizationID != nil {
+		if _, err := s.orgRepo.FindByID(*aspiration.OrganizationID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("organisasi tujuan tidak ditemukan")
+			}
+			return err
+		}
+	}
+
+	student, err := s.studentRepo.FindByUserID(int(userID))
+	if err != nil {
+		return err
+	}
+	if student == nil {
+		return errors.New("data mahasiswa tidak ditemukan")
+	}
+
+	aspiration.Moderation = models.AspirationModerationApproved
+	aspiration.FlagReason = ""
+	if matches := s.wordFilter.Match(aspiration.Title + " " + aspiration.Content); len(matches) > 0 {
+		aspiration.Moderation = models.AspirationModerationPending
+		aspiration.FlagReason = fmt.Sprintf("mengandung kata terlarang: %s", strings.Join(matches, ", "))
+	}
+
+	aspiration.UserID = userID
+	aspiration.StudentID = &student.ID
+	aspiration.Status = models.AspirationStatusReceived
+	return s.repository.Transaction(func(tx *repositories.AspirationRepository) error {
+		if err := tx.LockStudent(student.ID); err != nil {
+			return err
+		}
+		if err := s.checkRateLimit(tx, student.ID); err != nil {
+			return err
+		}
+		return tx.Create(aspiration)
+	})
+}
+
+// GetInbox mendapatkan kotak masuk aspirasi untuk admin. Pengirim aspirasi anonim disembunyikan.
+// Tanpa filter moderasi hanya aspirasi yang sudah disetujui yang ditampilkan; "all" menampilkan semuanya.
+func (s *AspirationService) GetInbox(filter repositories.AspirationFilter, limit, offset int) ([]models.Aspiration, int64, error) {
+	switch filter.Moderation {
+	case "":
+		filter.Moderation = models.AspirationModerationApproved
+	case "all":
+		filter.Moderation = ""
+	}
+	aspirations, total, err := s.repository.GetAll(filter, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range aspirations {
+		hideAnonymousAuthor(&aspirations[i])
+	}
+	return aspirations, total, nil
+}
+
+// GetModerationQueue mendapatkan aspirasi yang ditandai filter konten dan menunggu moderasi.
+func (s *AspirationService) GetModerationQueue(limit, offset int) ([]models.Aspiration, int64, error) {
+	return s.GetInbox(repositories.AspirationFilter{Moderation: models.AspirationModerationPending}, limit, offset)
+}
+
+// Moderate menyetujui, menyembunyikan, atau menandai aspirasi sebagai penyalahgunaan.
+func (s *AspirationService) Moderate(id uint, action, note string, adminID uint) (*models.Aspiration, error) {
+	moderation, ok := moderationActions[strings.ToLower(strings.TrimSpace(action))]
+	if !ok {
+		return nil, ErrInvalidModerationAction
+	}
+	aspiration, err := s.findAspiration(id)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	aspiration.Moderation = moderation
+	aspiration.ModeratedByID = &adminID
+	aspiration.ModeratedAt = &now
+	aspiration.ModerationNote = strings.TrimSpace(note)
+	if err := s.repository.SaveModeration(aspiration); err != nil {
+		return nil, err
+	}
+	hideAnonymousAuthor(aspiration)
+	return aspiration, nil
+}
+
+// GetAspirationByID mendapatkan aspirasi untuk admin. Pengirim aspirasi anonim disembunyikan.
+func (s *AspirationService) GetAspirationByID(id uint) (*models.Aspiration, error) {
+	aspiration, err := s.findAspiration(id)
+	if err != nil {
+		return nil, err
+	}
+	hideAnonymousAuthor(aspiration)
+	return aspiration, nil
+}
+
+// canTransitionAspiration memeriksa apakah status aspirasi boleh berpindah dari from ke to.
+func canTransitionAspiration(from, to string) bool {
+	for _, allowed := range aspirationTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// findAspiration mendapatkan aspirasi lengkap tanpa menyembunyikan pengirimnya.
+func (s *AspirationService) findAspiration(id uint) (*models.Aspiration, error) {
+	aspiration, err := s.repository.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("aspirasi tidak ditemukan")
+		}
+		return nil, err
+	}
+	return aspiration, nil
+}
+
+// ChangeStatus memindahkan status aspirasi dan mencatatnya di riwayat status.
+func (s *AspirationService) ChangeStatus(id uint, toStatus, note string, adminID uint) (*models.Aspiration, error) {
+	aspiration, err := s.findAspiration(id)
+	if err != nil {
+		return nil, err
+	}
+	if !canTransitionAspiration(aspiration.Status, toStatus) {
+		return nil, ErrInvalidAspirationTransition
+	}
+
+	history := &models.AspirationStatusHistory{
+		AspirationID: aspiration.ID,
+		FromStatus:   aspiration.Status,
+		ToStatus:     toStatus,
+		ChangedByID:  adminID,
+		Note:         strings.TrimSpace(note),
+	}
+	aspiration.Status = toStatus
+	if err := s.repository.SaveStatusChange(aspiration, history); err != nil {
+		return nil, err
+	}
+	return s.GetAspirationByID(id)
+}
+
+// AddResponse menambahkan tanggapan resmi ke utas aspirasi.
+func (s *AspirationService) AddResponse(id uint, content string, adminID uint) (*models.AspirationResponse, error) {
+	content = strings.TrimSpace(content)
+	if content == "" {
+		return nil, errors.New("isi tanggapan tidak boleh kosong")
+	}
+	aspiration, err := s.findAspiration(id)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.AspirationResponse{
+		AspirationID: aspiration.ID,
+		ResponderID:  adminID,
+		Content:      content,
+	}
+	if err := s.repository.CreateResponse(response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// GetMyAspirations mendapatkan aspirasi milik mahasiswa pemilik token.
+func (s *AspirationService) GetMyAspirations(userID uint, limit, offset int) ([]models.Aspiration, int64, error) {
+	student, err := s.studentRepo.FindByUserID(int(userID))
+	if err != nil {
+		return nil, 0, err
+	}
+	if student == nil {
+		return nil, 0, errors.New("data mahasiswa tidak ditemukan")
+	}
+	return s.repository.GetByStudentID(student.ID, limit, offset)
+}
+
+// GetMyAspirationByID mendapatkan aspirasi beserta utas tanggapan dan riwayat statusnya
+// jika aspirasi tersebut milik mahasiswa pemilik token.
+func (s *AspirationService) GetMyAspirationByID(id, userID uint) (*models.Aspiration, error) {
+	aspiration, err := s.findAspiration(id)
+	if err != nil {
+		return nil, err
+	}
+	if aspiration.UserID != userID {
+		return nil, ErrAspirationForbidden
+	}
+	return aspiration, nil
+}
+
+// RevealAuthor membuka identitas pengirim aspirasi untuk penanganan penyalahgunaan.
+// Setiap pembukaan dicatat beserta admin dan alasannya.
+func (s *AspirationService) RevealAuthor(id, adminID uint, reason string) (*models.Student, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, errors.New("alasan membuka identitas pengirim wajib diisi")
+	}
+
+	aspiration, err := s.findAspiration(id)
+	if err != nil {
+		return nil, err
+	}
+	if aspiration.Student == nil {
+		return nil, errors.New("data pengirim aspirasi tidak ditemukan")
+	}
+
+	access := &models.AspirationAuthorAccess{
+		AspirationID: aspiration.ID,
+		AdminID:      adminID,
+		Reason:       reason,
+	}
+	if err := s.repository.LogAuthorAccess(access); err != nil {
+		return nil, err
+	}
+	return aspiration.Student, nil
+}
+
+// voteTarget mendapatkan aspirasi yang menerima dukungan. Dukungan untuk aspirasi
+// yang sudah digabung diarahkan ke aspirasi tujuannya. Aspirasi yang belum disetujui
+// moderasi dianggap tidak ada.
+func (s *AspirationService) voteTarget(id uint) (*models.Aspiration, error) {
+	aspiration, err := s.findAspiration(id)
+	if err != nil {
+		return nil, err
+	}
+	if aspiration.MergedIntoID != nil {
+		if aspiration, err = s.findAspiration(*aspiration.MergedIntoID); err != nil {
+			return nil, err
+		}
+	}
+	if aspiration.Moderation != models.AspirationModerationApproved {
+		return nil, errors.New("aspirasi tidak ditemukan")
+	}
+	return aspiration, nil
+}
+
+// studentForVote mendapatkan data mahasiswa pemilik token.
+func (s *AspirationService) studentForVote(userID uint) (*models.Student, error) {
+	student, err := s.studentRepo.FindByUserID(int(userID))
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, errors.New("data mahasiswa tidak ditemukan")
+	}
+	return student, nil
+}
+
+// Vote mencatat dukungan mahasiswa pemilik token pada aspirasi. Satu mahasiswa hanya dapat
+// memberikan satu dukungan per aspirasi.
+func (s *AspirationService) Vote(id, userID uint) (*AspirationVoteResult, error) {
+	student, err := s.studentForVote(userID)
+	if err != nil {
+		return nil, err
+	}
+	aspiration, err := s.voteTarget(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repository.FindVote(aspiration.ID, student.ID); err == nil {
+		return nil, ErrAlreadyVoted
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	vote := &models.AspirationVote{AspirationID: aspiration.ID, StudentID: student.ID}
+	if err := s.repository.AddVote(vote); err != nil {
+		// Dukungan ganda yang bersamaan ditolak oleh unique index
+		if _, findErr := s.repository.FindVote(aspiration.ID, student.ID); findErr == nil {
+			return nil, ErrAlreadyVoted
+		}
+		return nil, err
+	}
+	return &AspirationVoteResult{ID: aspiration.ID, VoteCount: aspiration.VoteCount + 1, Voted: true}, nil
+}
+
+// Unvote membatalkan dukungan mahasiswa pemilik token pada aspirasi.
+func (s *AspirationService) Unvote(id, userID uint) (*AspirationVoteResult, error) {
+	student, err := s.studentForVote(userID)
+	if err != nil {
+		return nil, err
+	}
+	aspiration, err := s.voteTarget(id)
+	if err != nil {
+		return nil, err
+	}
+
+	vote, err := s.repository.FindVote(aspiration.ID, student.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("anda belum mendukung aspirasi ini")
+		}
+		return nil, err
+	}
+	if err := s.repository.RemoveVote(vote); err != nil {
+		return nil, err
+	}
+	result := &AspirationVoteResult{ID: aspiration.ID, VoteCount: aspiration.VoteCount}
+	if result.VoteCount > 0 {
+		result.VoteCount--
+	}
+	return result, nil
+}
+
+// GetTrending mengurutkan aspirasi aktif berdasarkan dukungan yang dibobot kebaruannya.
+// Setiap dukungan bernilai 0.5^(umur/half-life), sehingga dukungan baru lebih berpengaruh
+// daripada dukungan lama. Hanya dukungan dalam jendela trending yang dihitung.
+// Identitas pengirim hanya ditampilkan kepada admin.
+func (s *AspirationService) GetTrending(limit int, isAdmin bool) ([]TrendingAspiration, error) {
+	now := time.Now()
+	votes, err := s.repository.GetVotesSince(now.Add(-s.trendingWindow),
+		[]string{models.AspirationStatusResolved, models.AspirationStatusDeclined})
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[uint]float64)
+	counts := make(map[uint]int)
+	for _, vote := range votes {
+		age := now.Sub(vote.CreatedAt)
+		if age < 0 {
+			age = 0
+		}
+		scores[vote.AspirationID] += math.Pow(0.5, age.Hours()/s.trendHalfLife.Hours())
+		counts[vote.AspirationID]++
+	}
+
+	ids := make([]uint, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+	aspirations, err := s.repository.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	trending := make([]TrendingAspiration, 0, len(aspirations))
+	for _, aspiration := range aspirations {
+		if isAdmin {
+			hideAnonymousAuthor(&aspiration)
+		} else {
+			hideAuthor(&aspiration)
+		}
+		trending = append(trending, TrendingAspiration{
+			Aspiration:  aspiration,
+			Score:       math.Round(scores[aspiration.ID]*1000) / 1000,
+			RecentVotes: counts[aspiration.ID],
+		})
+	}
+	sort.SliceStable(trending, func(i, j int) bool {
+		if trending[i].Score != trending[j].Score {
+			return trending[i].Score > trending[j].Score
+		}
+		if trending[i].VoteCount != trending[j].VoteCount {
+			return trending[i].VoteCount > trending[j].VoteCount
+		}
+		return trending[i].CreatedAt.After(trending[j].CreatedAt)
+	})
+	if limit > 0 && len(trending) > limit {
+		trending = trending[:limit]
+	}
+	return trending, nil
+}
+
+// MergeAspiration menggabungkan aspirasi duplikat ke aspirasi tujuan. Dukungan aspirasi
+// sumber dipindahkan ke tujuan dan mahasiswa yang mendukung keduanya hanya dihitung sekali.
+func (s *AspirationService) MergeAspiration(sourceID, targetID uint) (*models.Aspiration, error) {
+	if sourceID == targetID {
+		return nil, ErrInvalidAspirationMerge
+	}
+	source, err := s.findAspiration(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.findAspiration(targetID)
+	if err != nil {
+		return nil, err
+	}
+	if source.MergedIntoID != nil || target.MergedIntoID != nil ||
+		target.Moderation != models.AspirationModerationApproved {
+		return nil, ErrInvalidAspirationMerge
+	}
+
+	if err := s.repository.MergeInto(source, target); err != nil {
+		return nil, err
+	}
+	return s.GetAspirationByID(target.ID)
+}
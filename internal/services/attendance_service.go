@@ -0,0 +1,291 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"bem_be/internal/utils"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidCheckInToken dikembalikan jika token presensi tidak sah atau sudah kedaluwarsa
+	ErrInvalidCheckInToken = errors.New("token presensi tidak valid atau sudah kedaluwarsa")
+	// ErrAlreadyCheckedIn dikembalikan jika mahasiswa sudah melakukan presensi pada kegiatan
+	ErrAlreadyCheckedIn = errors.New("anda sudah melakukan presensi pada kegiatan ini")
+	// ErrCheckInClosed dikembalikan jika presensi dilakukan di luar waktu kegiatan
+	ErrCheckInClosed = errors.New("presensi kegiatan belum dibuka atau sudah ditutup")
+)
+
+// checkInAudience menandai token presensi. Token presensi ditandatangani dengan CHECKIN_TOKEN_SECRET
+// dan auth.ValidateToken menolak token yang memiliki audience, sehingga QR presensi tidak dapat
+// dipakai sebagai token login.
+const checkInAudience = "activity-checkin"
+
+// checkInClaims adalah isi token presensi yang ditampilkan sebagai QR code.
+type checkInClaims struct {
+	ActivityID      uint  `json:"activity_id"`
+	OccurrenceStart int64 `json:"occurrence_start"`
+	jwt.StandardClaims
+}
+
+// CheckInToken adalah token presensi yang diterbitkan untuk satu pertemuan kegiatan.
+type CheckInToken struct {
+	ActivityID      uint      `json:"activity_id"`
+	OccurrenceStart time.Time `json:"occurrence_start"`
+	Token           string    `json:"token"`
+	ExpiresAt       time.Time `json:"expires_at"`
+}
+
+// AttendanceReport adalah rekap presensi sebuah kegiatan.
+type AttendanceReport struct {
+	ActivityID         uint                        `json:"activity_id"`
+	Title              string                      `json:"title"`
+	OccurrenceStart    *time.Time                  `json:"occurrence_start,omitempty"`
+	TotalAttended      int                         `json:"total_attended"`
+	TotalRegistered    int                         `json:"total_registered"`
+	RegisteredAttended int                         `json:"registered_attended"`
+	WalkIns            int                         `json:"walk_ins"`
+	Attendees          []models.ActivityAttendance `json:"attendees"`
+	Absentees          []models.Student            `json:"absentees"`
+}
+
+// AttendanceService adalah service untuk presensi kegiatan menggunakan QR code.
+type AttendanceService struct {
+	repository       *repositories.AttendanceRepository
+	activityRepo     *repositories.ActivityRepository
+	studentRepo      *repositories.StudentRepository
+	registrationRepo *repositories.RegistrationRepository
+	occurrenceRepo   *repositories.OccurrenceRepository
+	authors          *AuthorService
+	secret           []byte
+	tokenTTL         time.Duration
+	openBefore       time.Duration
+}
+
+// NewAttendanceService membuat service presensi baru.
+func NewAttendanceService(db *gorm.DB) *AttendanceService {
+	return &AttendanceService{
+		repository:       repositories.NewAttendanceRepository(),
+		activityRepo:     repositories.NewActivityRepository(),
+		studentRepo:      repositories.NewStudentRepository(),
+		registrationRepo: repositories.NewRegistrationRepository(),
+		occurrenceRepo:   repositories.NewOccurrenceRepository(),
+		authors:          NewAuthorService(db),
+		secret:           []byte(os.Getenv("CHECKIN_TOKEN_SECRET")),
+		tokenTTL:         time.Duration(utils.GetEnvAsInt("CHECKIN_TOKEN_TTL_SECONDS", 60)) * time.Second,
+		openBefore:       time.Duration(utils.GetEnvAsInt("CHECKIN_OPEN_BEFORE_MINUTES", 30)) * time.Minute,
+	}
+}
+
+// findActivity mendapatkan kegiatan dengan pesan error yang sesuai.
+func (s *AttendanceService) findActivity(activityID uint) (*models.Activity, error) {
+	activity, err := s.activityRepo.FindByID(activityID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrActivityNotFound
+		}
+		return nil, err
+	}
+	return activity, nil
+}
+
+// occurrences menjabarkan pertemuan kegiatan beserta perubahannya.
+func (s *AttendanceService) occurrences(activity *models.Activity) ([]Occurrence, error) {
+	overrides, err := s.occurrenceRepo.GetByActivityIDs([]uint{activity.ID})
+	if err != nil {
+		return nil, err
+	}
+	return expandActivity(activity, overrides), nil
+}
+
+// checkInOpen memeriksa apakah waktu t berada dalam jendela presensi pertemuan.
+func (s *AttendanceService) checkInOpen(occurrence Occurrence, t time.Time) bool {
+	return !occurrence.Cancelled &&
+		!t.Before(occurrence.StartDate.Add(-s.openBefore)) && !t.After(occurrence.EndDate)
+}
+
+// IssueToken menerbitkan token presensi berumur pendek untuk pertemuan yang sedang berlangsung,
+// untuk ditampilkan sebagai QR code.
+func (s *AttendanceService) IssueToken(activityID, userID uint, isAdmin bool) (*CheckInToken, error) {
+	activity, err := s.findActivity(activityID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authors.AuthorizeActivity(activity, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	occurrences, err := s.occurrences(activity)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var current *Occurrence
+	for i := range occurrences {
+		if s.checkInOpen(occurrences[i], now) {
+			current = &occurrences[i]
+			break
+		}
+	}
+	if current == nil {
+		return nil, ErrCheckInClosed
+	}
+
+	expiresAt := now.Add(s.tokenTTL)
+	claims := &checkInClaims{
+		ActivityID:      activity.ID,
+		OccurrenceStart: current.OccurrenceStart.Unix(),
+		StandardClaims: jwt.StandardClaims{
+			Audience:  checkInAudience,
+			Subject:   fmt.Sprintf("%d", activity.ID),
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &CheckInToken{
+		ActivityID:      activity.ID,
+		OccurrenceStart: current.OccurrenceStart,
+		Token:           token,
+		ExpiresAt:       expiresAt,
+	}, nil
+}
+
+// parseToken memverifikasi tanda tangan, masa berlaku, dan peruntukan token presensi.
+func (s *AttendanceService) parseToken(tokenString string) (*checkInClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &checkInClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return s.secret, nil
+	})
+	if err != nil {
+		return nil, ErrInvalidCheckInToken
+	}
+
+	claims, ok := token.Claims.(*checkInClaims)
+	if !ok || !token.Valid || !claims.VerifyAudience(checkInAudience, true) || claims.ActivityID == 0 {
+		return nil, ErrInvalidCheckInToken
+	}
+	return claims, nil
+}
+
+// CheckIn mencatat presensi mahasiswa yang login menggunakan token dari QR code.
+func (s *AttendanceService) CheckIn(tokenString string, userID uint) (*models.ActivityAttendance, error) {
+	claims, err := s.parseToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	activity, err := s.findActivity(claims.ActivityID)
+	if err != nil {
+		return nil, err
+	}
+	occurrences, err := s.occurrences(activity)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	var current *Occurrence
+	for i := range occurrences {
+		if occurrences[i].OccurrenceStart.Unix() == claims.OccurrenceStart {
+			current = &occurrences[i]
+			break
+		}
+	}
+	if current == nil || !s.checkInOpen(*current, now) {
+		return nil, ErrCheckInClosed
+	}
+
+	student, err := s.studentRepo.FindByUserID(int(userID))
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, errors.New("data mahasiswa tidak ditemukan")
+	}
+
+	if _, err := s.repository.FindByOccurrenceAndStudent(activity.ID, current.OccurrenceStart, student.ID); err == nil {
+		return nil, ErrAlreadyCheckedIn
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	attendance := &models.ActivityAttendance{
+		ActivityID:      activity.ID,
+		OccurrenceStart: current.OccurrenceStart,
+		StudentID:       student.ID,
+		CheckedInAt:     now,
+	}
+	if err := s.repository.Create(attendance); err != nil {
+		// Pindaian ganda yang bersamaan ditolak oleh unique index
+		if _, findErr := s.repository.FindByOccurrenceAndStudent(activity.ID, current.OccurrenceStart, student.ID); findErr == nil {
+			return nil, ErrAlreadyCheckedIn
+		}
+		return nil, err
+	}
+	attendance.Activity = activity
+	return attendance, nil
+}
+
+// GetAttendanceReport merekap presensi kegiatan dan membandingkannya dengan daftar pendaftar.
+// Jika occurrenceStart diisi, rekap hanya untuk pertemuan tersebut.
+func (s *AttendanceService) GetAttendanceReport(activityID uint, occurrenceStart *time.Time, userID uint, isAdmin bool) (*AttendanceReport, error) {
+	activity, err := s.findActivity(activityID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authors.AuthorizeActivity(activity, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	attendances, err := s.repository.GetByActivityID(activityID, occurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+	registrations, err := s.registrationRepo.GetByActivityID(activityID, models.RegistrationRegistered)
+	if err != nil {
+		return nil, err
+	}
+
+	attended := make(map[uint]bool, len(attendances))
+	for _, attendance := range attendances {
+		attended[attendance.StudentID] = true
+	}
+
+	report := &AttendanceReport{
+		ActivityID:      activity.ID,
+		Title:           activity.Title,
+		OccurrenceStart: occurrenceStart,
+		TotalAttended:   len(attendances),
+		TotalRegistered: len(registrations),
+		Attendees:       attendances,
+		Absentees:       []models.Student{},
+	}
+	registered := make(map[uint]bool, len(registrations))
+	for _, registration := range registrations {
+		registered[registration.StudentID] = true
+		if attended[registration.StudentID] {
+			report.RegisteredAttended++
+		} else if registration.Student != nil {
+			report.Absentees = append(report.Absentees, *registration.Student)
+		}
+	}
+	for studentID := range attended {
+		if !registered[studentID] {
+			report.WalkIns++
+		}
+	}
+
+	return report, nil
+}
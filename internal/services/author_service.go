@@ -0,0 +1,249 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// departmentCategoryID adalah kategori organisasi untuk departemen (lihat DepartmentRepository).
+const departmentCategoryID = 2
+
+var (
+	// ErrNotOfficer dikembalikan jika pengguna bukan admin maupun pengurus organisasi
+	ErrNotOfficer = errors.New("hanya admin atau pengurus organisasi yang dapat mengelola konten")
+	// ErrContentForbidden dikembalikan jika konten dimiliki organisasi yang tidak dikelola pengguna
+	ErrContentForbidden = errors.New("anda tidak berhak mengelola konten milik organisasi lain")
+	// ErrNotActivityOrganizer dikembalikan jika user bukan penyelenggara kegiatan
+	ErrNotActivityOrganizer = errors.New("hanya penyelenggara kegiatan yang dapat melakukan tindakan ini")
+	// ErrContentOwnerRequired dikembalikan jika pengurus beberapa organisasi tidak memilih pemilik konten
+	ErrContentOwnerRequired = errors.New("pilih organisasi pemilik konten (bem_id, association_id, atau department_id)")
+)
+
+// ContentOwner adalah pemilik sebuah konten: BEM, himpunan/UKM, atau departemen.
+// Konten tanpa pemilik adalah konten umum yang hanya dapat dikelola admin.
+type ContentOwner struct {
+	BEMID         *uint
+	AssociationID *uint
+	DepartmentID  *uint
+}
+
+// isEmpty memeriksa apakah konten tidak dimiliki organisasi mana pun.
+func (o ContentOwner) isEmpty() bool {
+	return o.BEMID == nil && o.AssociationID == nil && o.DepartmentID == nil
+}
+
+// Author adalah pengguna yang membuat atau mengubah konten beserta organisasi yang dikelolanya.
+type Author struct {
+	UserID  uint
+	IsAdmin bool
+	// BEMID terisi jika pengguna adalah ketua, wakil ketua, atau sekretaris BEM saat ini
+	BEMID *uint
+	// Organizations adalah organisasi yang periode kepengurusan terbarunya memuat pengguna
+	// sebagai ketua, wakil ketua, atau sekretaris
+	Organizations []models.Organization
+}
+
+// IsOfficer memeriksa apakah pengguna mengelola setidaknya satu organisasi.
+func (a *Author) IsOfficer() bool {
+	return a.BEMID != nil || len(a.Organizations) > 0
+}
+
+// managesOrganization memeriksa apakah pengguna adalah pengurus organisasi dengan ID tersebut.
+func (a *Author) managesOrganization(id uint) bool {
+	for _, organization := range a.Organizations {
+		if organization.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// OrganizationIDs mengembalikan ID semua organisasi yang dikelola pengguna.
+func (a *Author) OrganizationIDs() []uint {
+	ids := make([]uint, 0, len(a.Organizations))
+	for _, organization := range a.Organizations {
+		ids = append(ids, organization.ID)
+	}
+	return ids
+}
+
+// Authorize memastikan pengguna boleh mengelola konten milik owner. Admin boleh mengelola
+// semua konten, sedangkan pengurus hanya konten yang seluruh pemiliknya adalah organisasinya.
+func (a *Author) Authorize(owner ContentOwner) error {
+	if a.IsAdmin {
+		return nil
+	}
+	if !a.IsOfficer() {
+		return ErrNotOfficer
+	}
+	if owner.isEmpty() {
+		return ErrContentForbidden
+	}
+	if owner.BEMID != nil && (a.BEMID == nil || *a.BEMID != *owner.BEMID) {
+		return ErrContentForbidden
+	}
+	if owner.AssociationID != nil && !a.managesOrganization(*owner.AssociationID) {
+		return ErrContentForbidden
+	}
+	if owner.DepartmentID != nil && !a.managesOrganization(*owner.DepartmentID) {
+		return ErrContentForbidden
+	}
+	return nil
+}
+
+// ClaimOwner menentukan pemilik konten yang akan disimpan. Jika pengurus tidak memilih
+// pemilik, konten diberikan ke satu-satunya organisasi yang dikelolanya. Departemen
+// disimpan sebagai department_id, himpunan dan UKM sebagai association_id.
+func (a *Author) ClaimOwner(owner ContentOwner) (ContentOwner, error) {
+	if a.IsAdmin || !owner.isEmpty() {
+		return owner, a.Authorize(owner)
+	}
+	if !a.IsOfficer() {
+		return owner, ErrNotOfficer
+	}
+	if len(a.Organizations) > 1 || (a.BEMID != nil && len(a.Organizations) > 0) {
+		return owner, ErrContentOwnerRequired
+	}
+
+	if a.BEMID != nil {
+		id := *a.BEMID
+		return ContentOwner{BEMID: &id}, nil
+	}
+	organization := a.Organizations[0]
+	id := organization.ID
+	if organization.CategoryID == departmentCategoryID {
+		return ContentOwner{DepartmentID: &id}, nil
+	}
+	return ContentOwner{AssociationID: &id}, nil
+}
+
+// AuthorService adalah service untuk menentukan organisasi yang dikelola seorang pengguna.
+type AuthorService struct {
+	studentRepo *repositories.StudentRepository
+	periodRepo  *repositories.PeriodRepository
+	bemRepo     *repositories.BemRepository
+}
+
+// NewAuthorService membuat service penulis konten baru.
+func NewAuthorService(db *gorm.DB) *AuthorService {
+	return &AuthorService{
+		studentRepo: repositories.NewStudentRepository(),
+		periodRepo:  repositories.NewPeriodRepository(),
+		bemRepo:     repositories.NewBemRepository(),
+	}
+}
+
+// ResolveAuthor mengumpulkan organisasi yang dikelola pengguna pada periode berjalan.
+// Period menyimpan user_id kampus sebagai ID pengurus, sedangkan BEM menyimpan ID mahasiswa.
+func (s *AuthorService) ResolveAuthor(userID uint, isAdmin bool) (*Author, error) {
+	author := &Author{UserID: userID, IsAdmin: isAdmin}
+	if isAdmin {
+		return author, nil
+	}
+
+	periods, err := s.periodRepo.FindCurrentByOfficer(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, period := range periods {
+		if period.Organization != nil {
+			author.Organizations = append(author.Organizations, *period.Organization)
+		}
+	}
+
+	bemID, err := s.findBEMPosition(userID, func(bem *models.BEM) []uint {
+		return []uint{bem.LeaderID, bem.CoLeaderID, bem.Secretary1ID, bem.Secretary2ID}
+	})
+	if err != nil {
+		return nil, err
+	}
+	author.BEMID = bemID
+	return author, nil
+}
+
+// findBEMPosition mengembalikan ID BEM terbaru jika mahasiswa dengan user ID tersebut menduduki
+// salah satu jabatan yang dikembalikan positions. BEM menyimpan ID mahasiswa, bukan user_id.
+func (s *AuthorService) findBEMPosition(userID uint, positions func(bem *models.BEM) []uint) (*uint, error) {
+	student, err := s.studentRepo.FindByUserID(int(userID))
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, nil
+	}
+	bem, err := s.bemRepo.FindLatest()
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for _, officerID := range positions(bem) {
+		if officerID != 0 && officerID == student.ID {
+			return &bem.ID, nil
+		}
+	}
+	return nil, nil
+}
+
+// activityOwner mengembalikan organisasi penyelenggara kegiatan.
+func activityOwner(activity *models.Activity) ContentOwner {
+	return ContentOwner{BEMID: activity.BEMID, AssociationID: activity.AssociationID, DepartmentID: activity.DepartmentID}
+}
+
+// AuthorizeActivity memastikan user adalah admin atau pengurus organisasi penyelenggara kegiatan
+// (ketua, wakil ketua, atau sekretaris pada periode berjalan).
+func (s *AuthorService) AuthorizeActivity(activity *models.Activity, userID uint, isAdmin bool) error {
+	author, err := s.ResolveAuthor(userID, isAdmin)
+	if err != nil {
+		return err
+	}
+	if err := author.Authorize(activityOwner(activity)); err != nil {
+		return ErrNotActivityOrganizer
+	}
+	return nil
+}
+
+// ResolveFinanceAuthor seperti ResolveAuthor, tetapi juga menyertakan organisasi dan BEM
+// tempat pengguna menjabat sebagai bendahara pada periode berjalan.
+func (s *AuthorService) ResolveFinanceAuthor(userID uint, isAdmin bool) (*Author, error) {
+	author, err := s.ResolveAuthor(userID, isAdmin)
+	if err != nil || isAdmin {
+		return author, err
+	}
+
+	periods, err := s.periodRepo.FindCurrentByTreasurer(userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, period := range periods {
+		if period.Organization != nil {
+			author.Organizations = append(author.Organizations, *period.Organization)
+		}
+	}
+	if author.BEMID == nil {
+		author.BEMID, err = s.findBEMPosition(userID, func(bem *models.BEM) []uint {
+			return []uint{bem.Treasurer1ID, bem.Treasurer2ID}
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return author, nil
+}
+
+// AuthorizeActivityFinance memastikan user boleh mengelola keuangan kegiatan: admin, pengurus
+// organisasi penyelenggara, atau bendahara organisasi penyelenggara pada periode berjalan.
+func (s *AuthorService) AuthorizeActivityFinance(activity *models.Activity, userID uint, isAdmin bool) error {
+	author, err := s.ResolveFinanceAuthor(userID, isAdmin)
+	if err != nil {
+		return err
+	}
+	if err := author.Authorize(activityOwner(activity)); err != nil {
+		return ErrNotActivityOrganizer
+	}
+	return nil
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// ErrBudgetLocked dikembalikan jika RAB diubah setelah proposal mulai ditinjau
+var ErrBudgetLocked = errors.New("RAB tidak dapat diubah setelah proposal ditinjau")
+
+// BudgetService adalah service untuk rencana anggaran biaya (RAB) proposal.
+type BudgetService struct {
+	repository   *repositories.BudgetRepository
+	proposalRepo *repositories.ProposalRepository
+	financeRepo  *repositories.FinanceRepository
+	authors      *AuthorService
+}
+
+// NewBudgetService membuat service RAB baru.
+func NewBudgetService(db *gorm.DB) *BudgetService {
+	return &BudgetService{
+		repository:   repositories.NewBudgetRepository(),
+		proposalRepo: repositories.NewProposalRepository(),
+		financeRepo:  repositories.NewFinanceRepository(),
+		authors:      NewAuthorService(db),
+	}
+}
+
+// BudgetCategoryReport membandingkan anggaran dan realisasi untuk satu kategori.
+// Unbudgeted menandai pengeluaran pada kategori yang tidak ada di RAB.
+type BudgetCategoryReport struct {
+	Category   string              `json:"category"`
+	Items      []models.BudgetItem `json:"items"`
+	Planned    float64             `json:"planned"`
+	Actual     float64             `json:"actual"`
+	Variance   float64             `json:"variance"`
+	OverBudget bool                `json:"over_budget"`
+	Unbudgeted bool                `json:"unbudgeted"`
+}
+
+// BudgetReport adalah laporan anggaran vs realisasi sebuah proposal.
+type BudgetReport struct {
+	ProposalID      uint                   `json:"proposal_id"`
+	ActivityID      uint                   `json:"activity_id"`
+	TotalPlanned    float64                `json:"total_planned"`
+	TotalActual     float64                `json:"total_actual"`
+	OverBudgetCount int                    `json:"over_budget_count"`
+	Lines           []BudgetCategoryReport `json:"lines"`
+}
+
+// normalizeCategory menyamakan penulisan kategori agar anggaran dan realisasi dapat dicocokkan.
+func normalizeCategory(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
+
+// validateBudgetItem memeriksa field wajib dan menghitung total baris anggaran.
+func validateBudgetItem(item *models.BudgetItem) error {
+	item.Category = strings.TrimSpace(item.Category)
+	if item.Category == "" {
+		return errors.New("kategori anggaran wajib diisi")
+	}
+	if item.Quantity <= 0 || item.UnitPrice < 0 {
+		return errors.New("jumlah dan harga satuan anggaran tidak valid")
+	}
+	item.Amount = item.Quantity * item.UnitPrice
+	return nil
+}
+
+// managedProposal memastikan proposal ada dan user adalah pengurus atau bendahara
+// penyelenggara kegiatannya.
+func (s *BudgetService) managedProposal(proposalID, userID uint, isAdmin bool) (*models.Proposal, error) {
+	proposal, err := s.proposalRepo.FindByID(proposalID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProposalNotFound
+		}
+		return nil, err
+	}
+	if proposal.Activity == nil {
+		return nil, ErrActivityNotFound
+	}
+	if err := s.authors.AuthorizeActivityFinance(proposal.Activity, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+// editableProposal memastikan user boleh mengelola RAB proposal dan RAB-nya masih boleh diubah.
+func (s *BudgetService) editableProposal(proposalID, userID uint, isAdmin bool) (*models.Proposal, error) {
+	proposal, err := s.managedProposal(proposalID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.Status != models.ProposalStatusSubmitted {
+		return nil, ErrBudgetLocked
+	}
+	return proposal, nil
+}
+
+// CreateItems menambahkan satu atau beberapa baris anggaran ke proposal dalam satu transaksi.
+func (s *BudgetService) CreateItems(proposalID uint, items []models.BudgetItem, userID uint, isAdmin bool) error {
+	if _, err := s.editableProposal(proposalID, userID, isAdmin); err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return errors.New("baris anggaran tidak boleh kosong")
+	}
+
+	for i := range items {
+		items[i].ProposalID = proposalID
+		if err := validateBudgetItem(&items[i]); err != nil {
+			return err
+		}
+	}
+	return s.repository.Transaction(func(tx *repositories.BudgetRepository) error {
+		for i := range items {
+			if err := tx.Create(&items[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// GetItems mendapatkan semua baris anggaran milik proposal jika user boleh mengelola RAB-nya.
+func (s *BudgetService) GetItems(proposalID, userID uint, isAdmin bool) ([]models.BudgetItem, error) {
+	if _, err := s.managedProposal(proposalID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return s.repository.GetByProposalID(proposalID)
+}
+
+// GetItemByID mendapatkan baris anggaran berdasarkan ID.
+func (s *BudgetService) GetItemByID(id uint) (*models.BudgetItem, error) {
+	item, err := s.repository.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("baris anggaran tidak ditemukan")
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// UpdateItem memperbarui baris anggaran selama proposal belum ditinjau.
+func (s *BudgetService) UpdateItem(item *models.BudgetItem, userID uint, isAdmin bool) error {
+	if _, err := s.editableProposal(item.ProposalID, userID, isAdmin); err != nil {
+		return err
+	}
+	if err := validateBudgetItem(item); err != nil {
+		return err
+	}
+	return s.repository.Update(item)
+}
+
+// DeleteItem menghapus baris anggaran selama proposal belum ditinjau.
+func (s *BudgetService) DeleteItem(id, userID uint, isAdmin bool) error {
+	item, err := s.GetItemByID(id)
+	if err != nil {
+		return err
+	}
+	if _, err := s.editableProposal(item.ProposalID, userID, isAdmin); err != nil {
+		return err
+	}
+	return s.repository.DeleteByID(id)
+}
+
+// GetBudgetReport membandingkan RAB proposal dengan pengeluaran yang tercatat pada kegiatannya.
+// Pengeluaran dicocokkan dengan anggaran berdasarkan kategori (tanpa membedakan huruf besar/kecil).
+// Pengeluaran pada kategori di luar RAB dilaporkan sebagai baris tanpa anggaran yang melebihi anggaran.
+func (s *BudgetService) GetBudgetReport(proposalID, userID uint, isAdmin bool) (*BudgetReport, error) {
+	proposal, err := s.managedProposal(proposalID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.repository.GetByProposalID(proposalID)
+	if err != nil {
+		return nil, err
+	}
+	expenses, err := s.financeRepo.SumExpensesByCategory(proposal.ActivityID)
+	if err != nil {
+		return nil, err
+	}
+
+	actualByCategory := map[string]float64{}
+	for _, e := range expenses {
+		actualByCategory[normalizeCategory(e.Category)] += e.Total
+	}
+
+	report := &BudgetReport{
+		ProposalID: proposal.ID,
+		ActivityID: proposal.ActivityID,
+		Lines:      []BudgetCategoryReport{},
+	}
+
+	index := map[string]int{}
+	for _, item := range items {
+		key := normalizeCategory(item.Category)
+		i, ok := index[key]
+		if !ok {
+			report.Lines = append(report.Lines, BudgetCategoryReport{
+				Category: item.Category,
+				Items:    []models.BudgetItem{},
+				Actual:   actualByCategory[key],
+			})
+			i = len(report.Lines) - 1
+			index[key] = i
+		}
+		report.Lines[i].Items = append(report.Lines[i].Items, item)
+		report.Lines[i].Planned += item.Amount
+	}
+
+	for _, e := range expenses {
+		report.TotalActual += e.Total
+		key := normalizeCategory(e.Category)
+		if _, budgeted := index[key]; budgeted {
+			continue
+		}
+		report.Lines = append(report.Lines, BudgetCategoryReport{
+			Category:   e.Category,
+			Items:      []models.BudgetItem{},
+			Actual:     actualByCategory[key],
+			Unbudgeted: true,
+		})
+		index[key] = len(report.Lines) - 1
+	}
+
+	for i := range report.Lines {
+		line := &report.Lines[i]
+		line.Variance = line.Planned - line.Actual
+		line.OverBudget = line.Actual > line.Planned
+		if line.OverBudget {
+			report.OverBudgetCount++
+		}
+		report.TotalPlanned += line.Planned
+	}
+
+	return report, nil
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"bem_be/internal/repositories"
+	"bem_be/internal/utils"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// CalendarService adalah service untuk membangun feed iCalendar kegiatan dan pengumuman.
+type CalendarService struct {
+	activityRepo     *repositories.ActivityRepository
+	occurrenceRepo   *repositories.OccurrenceRepository
+	announcementRepo *repositories.AnnouncementRepository
+	uidDomain        string
+	historyDays      int
+}
+
+// NewCalendarService membuat service kalender baru.
+func NewCalendarService(db *gorm.DB) *CalendarService {
+	return &CalendarService{
+		activityRepo:     repositories.NewActivityRepository(),
+		occurrenceRepo:   repositories.NewOccurrenceRepository(),
+		announcementRepo: repositories.NewAnnouncementRepository(),
+		uidDomain:        utils.GetEnvWithDefault("CALENDAR_UID_DOMAIN", "bem.del.ac.id"),
+		historyDays:      utils.GetEnvAsInt("CALENDAR_HISTORY_DAYS", 180),
+	}
+}
+
+// BuildFeed membangun feed iCalendar dari kegiatan sesuai filter.
+// Kegiatan berulang dijabarkan menjadi satu event per pertemuan.
+// Pengumuman terjadwal hanya disertakan jika includeAnnouncements bernilai true,
+// karena pengumuman tidak terikat pada organisasi tertentu.
+func (s *CalendarService) BuildFeed(name string, filter repositories.ActivityFilter, includeAnnouncements bool) (string, error) {
+	from := time.Now().AddDate(0, 0, -s.historyDays)
+	filter.From = &from
+
+	activities, err := s.activityRepo.FindAll(filter)
+	if err != nil {
+		return "", err
+	}
+
+	ids := make([]uint, 0, len(activities))
+	for _, activity := range activities {
+		ids = append(ids, activity.ID)
+	}
+	overrides, err := s.occurrenceRepo.GetByActivityIDs(ids)
+	if err != nil {
+		return "", err
+	}
+
+	events := make([]utils.ICalEvent, 0, len(activities))
+	for i := range activities {
+		for _, occurrence := range occurrencesInRange(expandActivity(&activities[i], overrides), &from, nil) {
+			uid := fmt.Sprintf("activity-%d@%s", occurrence.ActivityID, s.uidDomain)
+			if occurrence.Recurring {
+				uid = fmt.Sprintf("activity-%d-%s@%s", occurrence.ActivityID, occurrence.OccurrenceStart.UTC().Format("20060102T150405Z"), s.uidDomain)
+			}
+			status := ""
+			if occurrence.Cancelled {
+				status = "CANCELLED"
+			}
+			events = append(events, utils.ICalEvent{
+				UID:          uid,
+				Sequence:     occurrence.UpdatedAt.Unix(),
+				Status:       status,
+				Summary:      occurrence.Title,
+				Description:  occurrence.Description,
+				Location:     occurrence.Location,
+				Start:        occurrence.StartDate,
+				End:          occurrence.EndDate,
+				LastModified: occurrence.UpdatedAt,
+			})
+		}
+	}
+
+	if includeAnnouncements {
+		announcements, err := s.announcementRepo.FindScheduled()
+		if err != nil {
+			return "", err
+		}
+		for _, announcement := range announcements {
+			if announcement.EndDate.Before(from) {
+				continue
+			}
+			events = append(events, utils.ICalEvent{
+				UID:          fmt.Sprintf("announcement-%d@%s", announcement.ID, s.uidDomain),
+				Sequence:     announcement.UpdatedAt.Unix(),
+				Summary:      announcement.Title,
+				Description:  announcement.Content,
+				Start:        *announcement.StartDate,
+				End:          *announcement.EndDate,
+				LastModified: announcement.UpdatedAt,
+			})
+		}
+	}
+
+	return utils.BuildICalendar(name, events), nil
+}
@@ -0,0 +1,263 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"errors"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// FinanceService adalah service untuk buku kas (pemasukan/pengeluaran) kegiatan.
+type FinanceService struct {
+	repository   *repositories.FinanceRepository
+	activityRepo *repositories.ActivityRepository
+	periodRepo   *repositories.PeriodRepository
+	authors      *AuthorService
+}
+
+// NewFinanceService membuat service keuangan baru.
+func NewFinanceService(db *gorm.DB) *FinanceService {
+	return &FinanceService{
+		repository:   repositories.NewFinanceRepository(),
+		activityRepo: repositories.NewActivityRepository(),
+		periodRepo:   repositories.NewPeriodRepository(),
+		authors:      NewAuthorService(db),
+	}
+}
+
+// LedgerEntry adalah transaksi beserta saldo berjalan setelah transaksi tersebut.
+type LedgerEntry struct {
+	models.FinanceEntry
+	RunningBalance float64 `json:"running_balance"`
+}
+
+// ActivityLedger berisi seluruh transaksi dan ringkasan saldo sebuah kegiatan.
+type ActivityLedger struct {
+	ActivityID uint          `json:"activity_id"`
+	Income     float64       `json:"income"`
+	Expense    float64       `json:"expense"`
+	Balance    float64       `json:"balance"`
+	Entries    []LedgerEntry `json:"entries"`
+}
+
+// ActivityFinanceSummary adalah ringkasan keuangan satu kegiatan dalam rekap organisasi.
+type ActivityFinanceSummary struct {
+	ActivityID     uint      `json:"activity_id"`
+	Title          string    `json:"title"`
+	StartDate      time.Time `json:"start_date"`
+	Income         float64   `json:"income"`
+	Expense        float64   `json:"expense"`
+	Balance        float64   `json:"balance"`
+	RunningBalance float64   `json:"running_balance"`
+}
+
+// OrganizationFinanceSummary adalah rekap keuangan seluruh kegiatan sebuah organisasi.
+type OrganizationFinanceSummary struct {
+	Income     float64                  `json:"income"`
+	Expense    float64                  `json:"expense"`
+	Balance    float64                  `json:"balance"`
+	Activities []ActivityFinanceSummary `json:"activities"`
+}
+
+// validateFinanceEntry memeriksa jenis, nominal, dan tanggal transaksi.
+func validateFinanceEntry(entry *models.FinanceEntry) error {
+	if entry.Type != models.FinanceEntryIncome && entry.Type != models.FinanceEntryExpense {
+		return errors.New("jenis transaksi harus income atau expense")
+	}
+	if entry.Amount <= 0 {
+		return errors.New("nominal transaksi harus lebih dari 0")
+	}
+	if entry.EntryDate.IsZero() {
+		return errors.New("tanggal transaksi wajib diisi")
+	}
+	return nil
+}
+
+// authorizeActivity memastikan user adalah admin, pengurus, atau bendahara penyelenggara kegiatan.
+func (s *FinanceService) authorizeActivity(activityID, userID uint, isAdmin bool) error {
+	activity, err := s.activityRepo.FindByID(activityID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrActivityNotFound
+		}
+		return err
+	}
+	return s.authors.AuthorizeActivityFinance(activity, userID, isAdmin)
+}
+
+// CreateEntry mencatat transaksi baru untuk sebuah kegiatan atas nama entry.RecordedByID.
+func (s *FinanceService) CreateEntry(entry *models.FinanceEntry, isAdmin bool) error {
+	if err := s.authorizeActivity(entry.ActivityID, entry.RecordedByID, isAdmin); err != nil {
+		return err
+	}
+	if err := validateFinanceEntry(entry); err != nil {
+		return err
+	}
+	return s.repository.Create(entry)
+}
+
+// GetEntryByID mendapatkan transaksi berdasarkan ID.
+func (s *FinanceService) GetEntryByID(id uint) (*models.FinanceEntry, error) {
+	entry, err := s.repository.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("transaksi tidak ditemukan")
+		}
+		return nil, err
+	}
+	return entry, nil
+}
+
+// UpdateEntry memperbarui transaksi yang ada. Transaksi tetap milik kegiatan semula.
+func (s *FinanceService) UpdateEntry(entry *models.FinanceEntry, userID uint, isAdmin bool) error {
+	stored, err := s.GetEntryByID(entry.ID)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeActivity(stored.ActivityID, userID, isAdmin); err != nil {
+		return err
+	}
+	entry.ActivityID = stored.ActivityID
+	entry.RecordedByID = stored.RecordedByID
+	if err := validateFinanceEntry(entry); err != nil {
+		return err
+	}
+	return s.repository.Update(entry)
+}
+
+// DeleteEntry menghapus transaksi beserta bukti pembayarannya.
+func (s *FinanceService) DeleteEntry(id, userID uint, isAdmin bool) error {
+	entry, err := s.GetEntryByID(id)
+	if err != nil {
+		return err
+	}
+	if err := s.authorizeActivity(entry.ActivityID, userID, isAdmin); err != nil {
+		return err
+	}
+	if err := s.repository.DeleteByID(id); err != nil {
+		return err
+	}
+	if entry.ReceiptPath != "" {
+		_ = os.Remove(entry.ReceiptPath)
+	}
+	return nil
+}
+
+// GetActivityLedger mendapatkan buku kas sebuah kegiatan dengan saldo berjalan.
+// Buku kas hanya dapat dibaca oleh user yang boleh mencatat transaksinya.
+func (s *FinanceService) GetActivityLedger(activityID, userID uint, isAdmin bool) (*ActivityLedger, error) {
+	if err := s.authorizeActivity(activityID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repository.GetByActivityID(activityID)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger := &ActivityLedger{
+		ActivityID: activityID,
+		Entries:    make([]LedgerEntry, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		if entry.Type == models.FinanceEntryIncome {
+			ledger.Income += entry.Amount
+		} else {
+			ledger.Expense += entry.Amount
+		}
+		ledger.Balance = ledger.Income - ledger.Expense
+		ledger.Entries = append(ledger.Entries, LedgerEntry{
+			FinanceEntry:   entry,
+			RunningBalance: ledger.Balance,
+		})
+	}
+
+	return ledger, nil
+}
+
+// GetOrganizationSummary merekap keuangan kegiatan sesuai filter, dengan saldo berjalan antar kegiatan.
+// Selain admin, user harus boleh mencatat transaksi seluruh kegiatan yang direkap.
+func (s *FinanceService) GetOrganizationSummary(filter repositories.ActivityFilter, userID uint, isAdmin bool) (*OrganizationFinanceSummary, error) {
+	activities, err := s.activityRepo.FindAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	author, err := s.authors.ResolveFinanceAuthor(userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	for i := range activities {
+		if err := author.Authorize(activityOwner(&activities[i])); err != nil {
+			return nil, ErrNotActivityOrganizer
+		}
+	}
+
+	ids := make([]uint, 0, len(activities))
+	for _, activity := range activities {
+		ids = append(ids, activity.ID)
+	}
+
+	totals, err := s.repository.SumByActivityIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byActivity := make(map[uint]repositories.ActivityTotals, len(totals))
+	for _, t := range totals {
+		byActivity[t.ActivityID] = t
+	}
+
+	summary := &OrganizationFinanceSummary{
+		Activities: make([]ActivityFinanceSummary, 0, len(activities)),
+	}
+	for _, activity := range activities {
+		t := byActivity[activity.ID]
+		summary.Income += t.Income
+		summary.Expense += t.Expense
+		summary.Balance = summary.Income - summary.Expense
+		summary.Activities = append(summary.Activities, ActivityFinanceSummary{
+			ActivityID:     activity.ID,
+			Title:          activity.Title,
+			StartDate:      activity.StartDate,
+			Income:         t.Income,
+			Expense:        t.Expense,
+			Balance:        t.Income - t.Expense,
+			RunningBalance: summary.Balance,
+		})
+	}
+
+	return summary, nil
+}
+
+// PeriodFilter membuat filter kegiatan untuk satu periode kepengurusan organisasi.
+// Periode dianggap berlaku sejak dibuat hingga periode berikutnya dibuat.
+func (s *FinanceService) PeriodFilter(periodID uint) (repositories.ActivityFilter, error) {
+	period, err := s.periodRepo.FindByID(periodID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repositories.ActivityFilter{}, errors.New("periode tidak ditemukan")
+		}
+		return repositories.ActivityFilter{}, err
+	}
+
+	orgID := uint(period.OrganizationID)
+	from := period.CreatedAt
+	filter := repositories.ActivityFilter{
+		OrganizationID: &orgID,
+		From:           &from,
+	}
+
+	next, err := s.periodRepo.FindNextByOrganizationID(period.OrganizationID, period.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return repositories.ActivityFilter{}, err
+	}
+	if next != nil {
+		to := next.CreatedAt
+		filter.To = &to
+	}
+
+	return filter, nil
+}
@@ -7,24 +7,48 @@ import (
 
 	"bem_be/internal/models"
 	"bem_be/internal/repositories"
+	"bem_be/internal/utils"
 )
 
 type GaleryService struct {
 	repository *repositories.GaleryRepository
+	tags       *TagService
 	db         *gorm.DB
 }
 
 func NewGaleryService(db *gorm.DB) *GaleryService {
 	return &GaleryService{
 		repository: repositories.NewGaleryRepository(),
+		tags:       NewTagService(db),
 	}
 }
 
-func (s *GaleryService) CreateGalery(galery *models.Galery) error {
+func galeryOwner(galery *models.Galery) ContentOwner {
+	return ContentOwner{BEMID: galery.BEMID, AssociationID: galery.AssociationID, DepartmentID: galery.DepartmentID}
+}
+
+func claimGaleryOwner(galery *models.Galery, author *Author) error {
+	owner, err := author.ClaimOwner(galeryOwner(galery))
+	if err != nil {
+		return err
+	}
+	galery.BEMID, galery.AssociationID, galery.DepartmentID = owner.BEMID, owner.AssociationID, owner.DepartmentID
+	return nil
+}
+
+func (s *GaleryService) CreateGalery(galery *models.Galery, author *Author) error {
+	if err := claimGaleryOwner(galery, author); err != nil {
+		return err
+	}
+	tags, err := s.tags.ResolveTags(galery.Tags)
+	if err != nil {
+		return err
+	}
+	galery.Tags = tags
 	return s.repository.Create(galery)
 }
 
-func (s *GaleryService) UpdateGalery(galery *models.Galery) error {
+func (s *GaleryService) UpdateGalery(galery *models.Galery, author *Author) error {
 	existingGalery, err := s.repository.FindByID(galery.ID)
 	if err != nil {
 		return err
@@ -32,18 +56,52 @@ func (s *GaleryService) UpdateGalery(galery *models.Galery) error {
 	if existingGalery == nil {
 		return errors.New("gambar tidak ditemukan")
 	}
-	return s.repository.Update(galery)
+	if err := author.Authorize(galeryOwner(existingGalery)); err != nil {
+		return err
+	}
+	if err := claimGaleryOwner(galery, author); err != nil {
+		return err
+	}
+	tags, err := s.tags.ResolveTags(galery.Tags)
+	if err != nil {
+		return err
+	}
+	galery.Tags = tags
+	if err := s.repository.Update(galery); err != nil {
+		return err
+	}
+	return s.tags.ReplaceTags(galery, tags)
 }
 
 func (s *GaleryService) GetGaleryByID(id uint) (*models.Galery, error) {
 	return s.repository.FindByID(id)
 }
 
-func (s *GaleryService) GetAllGalerys(limit, offset int) ([]models.Galery, int64, error) {
-	return s.repository.GetAllGalerys(limit, offset)
+func (s *GaleryService) GetManagedGaleryByID(id uint, author *Author) (*models.Galery, error) {
+	galery, err := s.repository.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := author.Authorize(galeryOwner(galery)); err != nil {
+		return nil, err
+	}
+	return galery, nil
+}
+
+// GetManagedGalerys mendapatkan galeri yang dapat dikelola author. Admin mendapatkan semua
+// galeri, pengurus hanya galeri milik organisasinya.
+func (s *GaleryService) GetManagedGalerys(author *Author, tag string, limit, offset int) ([]models.Galery, int64, error) {
+	var owners *repositories.OwnerScope
+	if !author.IsAdmin {
+		if !author.IsOfficer() {
+			return nil, 0, ErrNotOfficer
+		}
+		owners = &repositories.OwnerScope{BEMID: author.BEMID, OrganizationIDs: author.OrganizationIDs()}
+	}
+	return s.repository.GetAllGalerys(utils.Slugify(tag), owners, limit, offset)
 }
 
-func (s *GaleryService) DeleteGalery(id uint) error {
+func (s *GaleryService) DeleteGalery(id uint, author *Author) error {
 	galery, err := s.repository.FindByID(id)
 	if err != nil {
 		return err
@@ -51,6 +109,9 @@ func (s *GaleryService) DeleteGalery(id uint) error {
 	if galery == nil {
 		return errors.New("gambar tidak ditemukan")
 	}
+	if err := author.Authorize(galeryOwner(galery)); err != nil {
+		return err
+	}
 	return s.repository.DeleteByID(id)
 }
 
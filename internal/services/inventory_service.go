@@ -0,0 +1,315 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInsufficientStock dikembalikan jika jumlah yang dipinjam melebihi sisa stok pada rentang waktu peminjaman
+	ErrInsufficientStock = errors.New("stok barang tidak mencukupi pada rentang waktu peminjaman")
+	// ErrItemUnavailable dikembalikan jika barang dalam kondisi rusak dan tidak dapat dipinjam
+	ErrItemUnavailable = errors.New("barang sedang rusak dan tidak dapat dipinjam")
+)
+
+// inventoryConditions adalah kondisi barang inventaris yang valid.
+var inventoryConditions = map[string]bool{
+	models.InventoryConditionGood:    true,
+	models.InventoryConditionFair:    true,
+	models.InventoryConditionDamaged: true,
+}
+
+// requestPlanLayouts adalah format tanggal rencana pinjam dan kembali pada permintaan peminjaman.
+var requestPlanLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02",
+}
+
+// parseRequestPlan mengubah rencana pinjam atau kembali menjadi waktu.
+func parseRequestPlan(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range requestPlanLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("format tanggal peminjaman tidak valid: %s", value)
+}
+
+// requestWindow mengembalikan rentang waktu peminjaman sebuah permintaan.
+// Rencana kembali yang hanya berupa tanggal dianggap berlaku sampai akhir hari tersebut.
+func requestWindow(request *models.Request) (time.Time, time.Time, error) {
+	start, err := parseRequestPlan(request.RequestPlan)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end, err := parseRequestPlan(request.ReturnPlan)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if len(strings.TrimSpace(request.ReturnPlan)) == len("2006-01-02") {
+		end = end.Add(24*time.Hour - time.Second)
+	}
+	if !end.After(start) {
+		return time.Time{}, time.Time{}, errors.New("rencana kembali harus setelah rencana pinjam")
+	}
+	return start, end, nil
+}
+
+// peakReserved menghitung jumlah barang terbanyak yang dipinjam bersamaan dalam rentang [start, end).
+func peakReserved(requests []models.Request, start, end time.Time, excludeID uint) uint {
+	type event struct {
+		at    time.Time
+		delta int
+	}
+	var events []event
+	now := time.Now()
+	for i := range requests {
+		if requests[i].ID == excludeID {
+			continue
+		}
+		reqStart, reqEnd, err := requestWindow(&requests[i])
+		if err != nil {
+			continue
+		}
+		// Barang yang terlambat dikembalikan tetap terpakai sampai pengembaliannya dikonfirmasi
+		if requests[i].Status == models.RequestStatusHandedOver && reqEnd.Before(now) {
+			reqEnd = end
+		}
+		if !reqStart.Before(end) || !reqEnd.After(start) {
+			continue
+		}
+		if reqStart.Before(start) {
+			reqStart = start
+		}
+		events = append(events,
+			event{at: reqStart, delta: int(requests[i].Quantity)},
+			event{at: reqEnd, delta: -int(requests[i].Quantity)})
+	}
+	// Pengembalian pada saat yang sama dengan peminjaman lain dihitung lebih dulu
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].at.Equal(events[j].at) {
+			return events[i].at.Before(events[j].at)
+		}
+		return events[i].delta < events[j].delta
+	})
+
+	current, peak := 0, 0
+	for _, e := range events {
+		current += e.delta
+		if current > peak {
+			peak = current
+		}
+	}
+	return uint(peak)
+}
+
+// ItemAvailability adalah ketersediaan barang inventaris pada suatu rentang waktu.
+type ItemAvailability struct {
+	Item      models.InventoryItem `json:"item"`
+	Start     time.Time            `json:"start"`
+	End       time.Time            `json:"end"`
+	Reserved  uint                 `json:"reserved"`
+	Available uint                 `json:"available"`
+}
+
+// newItemAvailability menghitung sisa stok barang dari permintaan yang disetujui atau sedang dipinjam.
+func newItemAvailability(item models.InventoryItem, requests []models.Request, start, end time.Time, excludeID uint) ItemAvailability {
+	availability := ItemAvailability{
+		Item:     item,
+		Start:    start,
+		End:      end,
+		Reserved: peakReserved(requests, start, end, excludeID),
+	}
+	if item.Condition != models.InventoryConditionDamaged && item.TotalStock > availability.Reserved {
+		availability.Available = item.TotalStock - availability.Reserved
+	}
+	return availability
+}
+
+// InventoryService adalah service untuk inventaris barang yang dapat dipinjam.
+type InventoryService struct {
+	repository  *repositories.InventoryRepository
+	requestRepo *repositories.RequestRepository
+}
+
+// NewInventoryService membuat service inventaris baru.
+func NewInventoryService(db *gorm.DB) *InventoryService {
+	return &InventoryService{
+		repository:  repositories.NewInventoryRepository(),
+		requestRepo: repositories.NewRequestRepository(),
+	}
+}
+
+// validateItem merapikan data barang dan memastikan tidak ada nama ganda.
+func (s *InventoryService) validateItem(item *models.InventoryItem) error {
+	item.Name = strings.TrimSpace(item.Name)
+	item.Condition = strings.ToLower(strings.TrimSpace(item.Condition))
+	if item.Name == "" {
+		return errors.New("nama barang tidak boleh kosong")
+	}
+	if item.Condition == "" {
+		item.Condition = models.InventoryConditionGood
+	}
+	if !inventoryConditions[item.Condition] {
+		return errors.New("kondisi barang tidak valid")
+	}
+
+	existing, err := s.repository.FindByName(strings.ToLower(item.Name))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != item.ID {
+		return errors.New("barang dengan nama tersebut sudah ada")
+	}
+	return nil
+}
+
+// CreateItem membuat barang inventaris baru.
+func (s *InventoryService) CreateItem(item *models.InventoryItem) error {
+	if err := s.validateItem(item); err != nil {
+		return err
+	}
+	return s.repository.Create(item)
+}
+
+// UpdateItem memperbarui barang inventaris yang ada.
+func (s *InventoryService) UpdateItem(item *models.InventoryItem) error {
+	if err := s.validateItem(item); err != nil {
+		return err
+	}
+	return s.repository.Update(item)
+}
+
+// GetItemByID mendapatkan barang inventaris berdasarkan ID.
+func (s *InventoryService) GetItemByID(id uint) (*models.InventoryItem, error) {
+	item, err := s.repository.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("barang tidak ditemukan")
+		}
+		return nil, err
+	}
+	return item, nil
+}
+
+// GetAllItems mendapatkan semua barang inventaris.
+func (s *InventoryService) GetAllItems(search, condition string) ([]models.InventoryItem, error) {
+	return s.repository.GetAll(strings.TrimSpace(search), strings.ToLower(condition))
+}
+
+// DeleteItem menghapus barang inventaris.
+func (s *InventoryService) DeleteItem(id uint) error {
+	if _, err := s.GetItemByID(id); err != nil {
+		return err
+	}
+	return s.repository.DeleteByID(id)
+}
+
+// GetAvailability menghitung sisa stok sebuah barang pada rentang waktu tertentu.
+func (s *InventoryService) GetAvailability(id uint, start, end time.Time) (*ItemAvailability, error) {
+	if !end.After(start) {
+		return nil, errors.New("waktu selesai harus setelah waktu mulai")
+	}
+	item, err := s.GetItemByID(id)
+	if err != nil {
+		return nil, err
+	}
+	requests, err := s.requestRepo.GetActiveByItemIDs([]uint{item.ID})
+	if err != nil {
+		return nil, err
+	}
+	availability := newItemAvailability(*item, requests, start, end, 0)
+	return &availability, nil
+}
+
+// GetAllAvailability menghitung sisa stok semua barang pada rentang waktu tertentu.
+func (s *InventoryService) GetAllAvailability(start, end time.Time) ([]ItemAvailability, error) {
+	if !end.After(start) {
+		return nil, errors.New("waktu selesai harus setelah waktu mulai")
+	}
+	items, err := s.repository.GetAll("", "")
+	if err != nil {
+		return nil, err
+	}
+	requests, err := s.requestRepo.GetActiveByItemIDs(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	byItem := make(map[uint][]models.Request)
+	for _, request := range requests {
+		byItem[*request.ItemID] = append(byItem[*request.ItemID], request)
+	}
+	availabilities := make([]ItemAvailability, 0, len(items))
+	for _, item := range items {
+		availabilities = append(availabilities, newItemAvailability(item, byItem[item.ID], start, end, 0))
+	}
+	return availabilities, nil
+}
+
+// CheckRequest memastikan permintaan peminjaman merujuk barang yang ada dan jumlahnya
+// tidak melebihi sisa stok pada rentang rencana pinjam sampai rencana kembali.
+// Nama permintaan diisi dengan nama barang.
+func (s *InventoryService) CheckRequest(request *models.Request) error {
+	return s.checkRequest(s.requestRepo, request, s.GetItemByID)
+}
+
+// CheckRequestLocked sama dengan CheckRequest, tetapi dijalankan di dalam transaksi tx dan
+// mengunci baris barang (SELECT ... FOR UPDATE) sampai transaksi selesai.
+func (s *InventoryService) CheckRequestLocked(tx *repositories.RequestRepository, request *models.Request) error {
+	return s.checkRequest(tx, request, func(id uint) (*models.InventoryItem, error) {
+		item, err := tx.LockItem(id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, errors.New("barang tidak ditemukan")
+			}
+			return nil, err
+		}
+		return item, nil
+	})
+}
+
+// checkRequest memeriksa sisa stok barang menggunakan findItem dan permintaan aktif dari requestRepo.
+func (s *InventoryService) checkRequest(requestRepo *repositories.RequestRepository, request *models.Request,
+	findItem func(id uint) (*models.InventoryItem, error)) error {
+	if request.ItemID == nil {
+		return errors.New("barang yang dipinjam wajib dipilih")
+	}
+	if request.Quantity == 0 {
+		return errors.New("jumlah barang harus lebih dari nol")
+	}
+	start, end, err := requestWindow(request)
+	if err != nil {
+		return err
+	}
+
+	item, err := findItem(*request.ItemID)
+	if err != nil {
+		return err
+	}
+	if item.Condition == models.InventoryConditionDamaged {
+		return ErrItemUnavailable
+	}
+	requests, err := requestRepo.GetActiveByItemIDs([]uint{item.ID})
+	if err != nil {
+		return err
+	}
+	availability := newItemAvailability(*item, requests, start, end, request.ID)
+	if request.Quantity > availability.Available {
+		return fmt.Errorf("%w: tersisa %d dari %d %s", ErrInsufficientStock, availability.Available, item.TotalStock, item.Name)
+	}
+
+	request.Name = item.Name
+	return nil
+}
@@ -0,0 +1,406 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"bem_be/internal/utils"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/skip2/go-qrcode"
+	"gorm.io/gorm"
+)
+
+// ErrLetterNotAvailable dikembalikan jika surat diminta untuk permintaan yang belum disetujui
+var ErrLetterNotAvailable = errors.New("surat peminjaman hanya tersedia untuk permintaan yang sudah disetujui")
+
+// defaultLetterTemplate berisi paragraf pembuka dan penutup surat peminjaman.
+// Template dapat diganti melalui BORROWING_LETTER_TEMPLATE dengan blok "opening" dan "closing" yang sama.
+const defaultLetterTemplate = `{{define "opening"}}Dengan hormat,
+
+Yang bertanda tangan di bawah ini, atas nama {{.Issuer}}, memberikan izin peminjaman barang inventaris kepada {{.OrganizationName}} dengan rincian sebagai berikut:{{end}}
+{{define "closing"}}Peminjam bertanggung jawab menjaga keutuhan barang selama masa peminjaman dan wajib mengembalikannya dalam kondisi baik paling lambat {{.ReturnDate}}. Kerusakan atau kehilangan barang menjadi tanggung jawab peminjam.
+
+Demikian surat peminjaman ini dibuat untuk dipergunakan sebagaimana mestinya. Keaslian surat dapat diperiksa dengan memindai kode QR di bawah ini.{{end}}`
+
+// indonesianMonths adalah nama bulan dalam bahasa Indonesia.
+var indonesianMonths = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// romanMonths adalah angka romawi bulan untuk nomor surat.
+var romanMonths = [...]string{"I", "II", "III", "IV", "V", "VI", "VII", "VIII", "IX", "X", "XI", "XII"}
+
+// formatIndonesianDate memformat tanggal seperti "17 Agustus 2026", beserta jam jika bukan tengah malam.
+func formatIndonesianDate(t time.Time) string {
+	date := fmt.Sprintf("%d %s %d", t.Day(), indonesianMonths[t.Month()-1], t.Year())
+	if t.Hour() != 0 || t.Minute() != 0 {
+		date += t.Format(" 15.04")
+	}
+	return date
+}
+
+// letterData adalah isi surat peminjaman yang tersedia untuk template.
+type letterData struct {
+	Number           string
+	Issuer           string
+	OrganizationName string
+	ItemName         string
+	Quantity         uint
+	BorrowDate       string
+	ReturnDate       string
+	RequesterName    string
+	RequesterNIM     string
+	ApproverName     string
+	ApproverPosition string
+	IssuedDate       string
+	VerifyURL        string
+}
+
+// LetterVerification adalah hasil pemeriksaan keaslian surat peminjaman.
+type LetterVerification struct {
+	Valid            bool      `json:"valid"`
+	Number           string    `json:"number"`
+	IssuedAt         time.Time `json:"issued_at"`
+	OrganizationName string    `json:"organization_name"`
+	ItemName         string    `json:"item_name"`
+	Quantity         uint      `json:"quantity"`
+	RequestPlan      string    `json:"request_plan"`
+	ReturnPlan       string    `json:"return_plan"`
+	RequestStatus    string    `json:"request_status"`
+}
+
+// LetterService adalah service untuk surat peminjaman barang.
+type LetterService struct {
+	repository  *repositories.LetterRepository
+	requests    *RequestService
+	studentRepo *repositories.StudentRepository
+	userRepo    *repositories.UserRepository
+	template    *template.Template
+	issuer      string
+	city        string
+	numberCode  string
+	baseURL     string
+}
+
+// NewLetterService membuat service surat peminjaman baru.
+func NewLetterService(db *gorm.DB) *LetterService {
+	return &LetterService{
+		repository:  repositories.NewLetterRepository(),
+		requests:    NewRequestService(db),
+		studentRepo: repositories.NewStudentRepository(),
+		userRepo:    repositories.NewUserRepository(),
+		template:    loadLetterTemplate(),
+		issuer:      utils.GetEnvWithDefault("LETTER_ISSUER", "Badan Eksekutif Mahasiswa IT Del"),
+		city:        utils.GetEnvWithDefault("LETTER_CITY", "Sitoluama"),
+		numberCode:  utils.GetEnvWithDefault("LETTER_NUMBER_CODE", "SP/BEM-ITDEL"),
+		baseURL:     strings.TrimRight(utils.GetEnvWithDefault("APP_BASE_URL", "http://localhost:8080"), "/"),
+	}
+}
+
+// loadLetterTemplate memuat template surat dari BORROWING_LETTER_TEMPLATE, atau template bawaan.
+func loadLetterTemplate() *template.Template {
+	if path := os.Getenv("BORROWING_LETTER_TEMPLATE"); path != "" {
+		tmpl, err := template.ParseFiles(path)
+		if err == nil && tmpl.Lookup("opening") != nil && tmpl.Lookup("closing") != nil {
+			return tmpl
+		}
+		log.Printf("Failed to load borrowing letter template %s, using built-in template: %v", path, err)
+	}
+	return template.Must(template.New("letter").Parse(defaultLetterTemplate))
+}
+
+// letterAvailable memeriksa apakah surat boleh diterbitkan untuk status permintaan.
+func letterAvailable(status string) bool {
+	switch status {
+	case models.RequestStatusApproved, models.RequestStatusHandedOver, models.RequestStatusReturned:
+		return true
+	}
+	return false
+}
+
+// newVerificationCode membuat kode verifikasi acak untuk QR code surat.
+func newVerificationCode() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// issue mendapatkan surat untuk permintaan, atau menerbitkan surat bernomor baru jika belum ada.
+func (s *LetterService) issue(request *models.Request) (*models.BorrowingLetter, error) {
+	letter, err := s.repository.FindByRequestID(request.ID)
+	if err == nil {
+		return letter, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	code, err := newVerificationCode()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	letter = &models.BorrowingLetter{
+		RequestID:        request.ID,
+		Year:             now.Year(),
+		VerificationCode: code,
+		IssuedAt:         now,
+	}
+	err = s.repository.CreateNext(letter, func(sequence uint) string {
+		return fmt.Sprintf("%03d/%s/%s/%d", sequence, s.numberCode, romanMonths[now.Month()-1], now.Year())
+	})
+	if err != nil {
+		// Permintaan yang sama bisa diterbitkan bersamaan; pakai surat yang sudah tersimpan
+		if existing, findErr := s.repository.FindByRequestID(request.ID); findErr == nil {
+			return existing, nil
+		}
+		return nil, err
+	}
+	return letter, nil
+}
+
+// letterItemName mengembalikan nama barang yang dicetak pada surat: nama barang inventaris
+// jika permintaan terhubung ke barang, selain itu nama pada permintaan.
+func letterItemName(request *models.Request) string {
+	if request.Item != nil {
+		return request.Item.Name
+	}
+	return request.Name
+}
+
+// verifyURL adalah alamat verifikasi yang disematkan di QR code surat.
+func (s *LetterService) verifyURL(code string) string {
+	return fmt.Sprintf("%s/api/letters/verify/%s", s.baseURL, code)
+}
+
+// buildLetterData mengumpulkan isi surat dari permintaan, peminjam, dan penyetuju.
+func (s *LetterService) buildLetterData(request *models.Request, letter *models.BorrowingLetter) (*letterData, error) {
+	start, err := parseRequestPlan(request.RequestPlan)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseRequestPlan(request.ReturnPlan)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &letterData{
+		Number:           letter.Number,
+		Issuer:           s.issuer,
+		OrganizationName: request.OrganizationName,
+		ItemName:         letterItemName(request),
+		Quantity:         request.Quantity,
+		BorrowDate:       formatIndonesianDate(start),
+		ReturnDate:       formatIndonesianDate(end),
+		VerifyURL:        s.verifyURL(letter.VerificationCode),
+		RequesterName:    "-",
+		ApproverName:     "-",
+	}
+	data.IssuedDate = fmt.Sprintf("%s, %d %s %d", s.city,
+		letter.IssuedAt.Day(), indonesianMonths[letter.IssuedAt.Month()-1], letter.IssuedAt.Year())
+
+	student, err := s.studentRepo.FindByUserID(request.RequesterID)
+	if err != nil {
+		return nil, err
+	}
+	if student != nil {
+		data.RequesterName = student.FullName
+		data.RequesterNIM = student.NIM
+	}
+	if request.ApproverID != nil {
+		approver, err := s.userRepo.FindByID(*request.ApproverID)
+		if err != nil {
+			return nil, err
+		}
+		if approver != nil {
+			data.ApproverName = approver.Username
+			data.ApproverPosition = approver.Position
+		}
+	}
+	return data, nil
+}
+
+// executeBlock menjalankan satu blok template surat.
+func (s *LetterService) executeBlock(name string, data *letterData) (string, error) {
+	var buf bytes.Buffer
+	if err := s.template.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// renderLetterPDF menyusun PDF surat peminjaman berukuran A4.
+func (s *LetterService) renderLetterPDF(data *letterData) ([]byte, error) {
+	opening, err := s.executeBlock("opening", data)
+	if err != nil {
+		return nil, err
+	}
+	closing, err := s.executeBlock("closing", data)
+	if err != nil {
+		return nil, err
+	}
+	qr, err := qrcode.Encode(data.VerifyURL, qrcode.Medium, 256)
+	if err != nil {
+		return nil, err
+	}
+
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(25, 20, 25)
+	pdf.SetAutoPageBreak(true, 20)
+	pdf.SetTitle("Surat Peminjaman "+data.Number, true)
+	pdf.SetAuthor(s.issuer, true)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+	width, _ := pdf.GetPageSize()
+	contentWidth := width - 50
+
+	// Kop surat
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.CellFormat(contentWidth, 7, tr(strings.ToUpper(s.issuer)), "", 1, "C", false, 0, "")
+	pdf.SetLineWidth(0.6)
+	pdf.Line(25, pdf.GetY()+2, width-25, pdf.GetY()+2)
+	pdf.Ln(8)
+
+	pdf.SetFont("Helvetica", "BU", 12)
+	pdf.CellFormat(contentWidth, 6, "SURAT PEMINJAMAN BARANG", "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.CellFormat(contentWidth, 6, tr("Nomor: "+data.Number), "", 1, "C", false, 0, "")
+	pdf.Ln(6)
+
+	pdf.MultiCell(contentWidth, 6, tr(opening), "", "J", false)
+	pdf.Ln(3)
+
+	rows := [][2]string{
+		{"Organisasi", data.OrganizationName},
+		{"Nama barang", data.ItemName},
+		{"Jumlah", fmt.Sprintf("%d unit", data.Quantity)},
+		{"Tanggal pinjam", data.BorrowDate},
+		{"Tanggal kembali", data.ReturnDate},
+		{"Penanggung jawab", strings.TrimSpace(data.RequesterName + " " + nimSuffix(data.RequesterNIM))},
+	}
+	for _, row := range rows {
+		pdf.CellFormat(45, 7, tr(row[0]), "1", 0, "L", false, 0, "")
+		pdf.CellFormat(contentWidth-45, 7, tr(row[1]), "1", 1, "L", false, 0, "")
+	}
+	pdf.Ln(5)
+
+	pdf.MultiCell(contentWidth, 6, tr(closing), "", "J", false)
+	pdf.Ln(8)
+
+	// Tanda tangan peminjam dan penyetuju
+	signatureTop := pdf.GetY()
+	half := contentWidth / 2
+	pdf.CellFormat(half, 6, "Peminjam,", "", 0, "C", false, 0, "")
+	pdf.CellFormat(half, 6, tr(data.IssuedDate), "", 1, "C", false, 0, "")
+	pdf.CellFormat(half, 6, "", "", 0, "C", false, 0, "")
+	pdf.CellFormat(half, 6, "Disetujui oleh,", "", 1, "C", false, 0, "")
+	pdf.SetY(signatureTop + 34)
+	pdf.SetFont("Helvetica", "BU", 11)
+	pdf.CellFormat(half, 6, tr(data.RequesterName), "", 0, "C", false, 0, "")
+	pdf.CellFormat(half, 6, tr(data.ApproverName), "", 1, "C", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(half, 5, tr(nimSuffix(data.RequesterNIM)), "", 0, "C", false, 0, "")
+	pdf.CellFormat(half, 5, tr(data.ApproverPosition), "", 1, "C", false, 0, "")
+	pdf.Ln(8)
+
+	// QR code verifikasi
+	pdf.RegisterImageOptionsReader("verification-qr", gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(qr))
+	qrTop := pdf.GetY()
+	pdf.ImageOptions("verification-qr", 25, qrTop, 30, 30, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+	pdf.SetXY(58, qrTop+8)
+	pdf.SetFont("Helvetica", "I", 9)
+	pdf.MultiCell(contentWidth-33, 5, tr("Pindai kode QR untuk memverifikasi keaslian surat ini:\n"+data.VerifyURL), "", "L", false)
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// nimSuffix memformat NIM peminjam untuk ditampilkan di surat.
+func nimSuffix(nim string) string {
+	if nim == "" {
+		return ""
+	}
+	return "NIM " + nim
+}
+
+// render menerbitkan (jika belum) dan menyusun PDF surat untuk permintaan.
+func (s *LetterService) render(request *models.Request) ([]byte, *models.BorrowingLetter, error) {
+	if !letterAvailable(request.Status) {
+		return nil, nil, ErrLetterNotAvailable
+	}
+	letter, err := s.issue(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := s.buildLetterData(request, letter)
+	if err != nil {
+		return nil, nil, err
+	}
+	pdf, err := s.renderLetterPDF(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pdf, letter, nil
+}
+
+// RenderLetter menyusun PDF surat peminjaman untuk admin.
+func (s *LetterService) RenderLetter(requestID uint) ([]byte, *models.BorrowingLetter, error) {
+	request, err := s.requests.findRequest(requestID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.render(request)
+}
+
+// RenderMyOrganizationLetter menyusun PDF surat peminjaman jika permintaan milik organisasi
+// mahasiswa pemilik token.
+func (s *LetterService) RenderMyOrganizationLetter(requestID, userID uint) ([]byte, *models.BorrowingLetter, error) {
+	request, err := s.requests.GetMyOrganizationRequest(requestID, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return s.render(request)
+}
+
+// VerifyLetter memeriksa keaslian surat berdasarkan kode verifikasi pada QR code.
+// Surat tidak lagi berlaku jika permintaannya sudah dibatalkan atau dihapus.
+func (s *LetterService) VerifyLetter(code string) (*LetterVerification, error) {
+	letter, err := s.repository.FindByVerificationCode(code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("surat peminjaman tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	verification := &LetterVerification{
+		Number:   letter.Number,
+		IssuedAt: letter.IssuedAt,
+	}
+	if request := letter.Request; request != nil {
+		verification.Valid = letterAvailable(request.Status)
+		verification.OrganizationName = request.OrganizationName
+		verification.ItemName = letterItemName(request)
+		verification.Quantity = request.Quantity
+		verification.RequestPlan = request.RequestPlan
+		verification.ReturnPlan = request.ReturnPlan
+		verification.RequestStatus = request.Status
+	}
+	return verification, nil
+}
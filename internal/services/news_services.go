@@ -3,34 +3,156 @@ package services
 import (
 	"bem_be/internal/models"
 	"bem_be/internal/repositories"
+	"bem_be/internal/utils"
 	"errors"
+	"fmt"
+	"html"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"gorm.io/gorm"
 )
 
+// newsStatuses adalah status publikasi berita yang valid.
+var newsStatuses = map[string]bool{
+	models.NewsStatusDraft:     true,
+	models.NewsStatusScheduled: true,
+	models.NewsStatusPublished: true,
+}
+
 // NewsService adalah service untuk operasi berita.
 type NewsService struct {
 	repository *repositories.NewsRepository
+	slugs      *SlugService
+	tags       *TagService
+	siteURL    string
+	baseURL    string
+	feedLimit  int
 }
 
 // NewNewsService membuat service berita baru.
 func NewNewsService(db *gorm.DB) *NewsService {
 	return &NewsService{
 		repository: repositories.NewNewsRepository(),
+		slugs:      NewSlugService(),
+		tags:       NewTagService(db),
+		siteURL:    strings.TrimRight(utils.GetEnvWithDefault("PUBLIC_SITE_URL", "http://localhost:3000"), "/"),
+		baseURL:    strings.TrimRight(utils.GetEnvWithDefault("APP_BASE_URL", "http://localhost:8080"), "/"),
+		feedLimit:  utils.GetEnvAsInt("NEWS_FEED_LIMIT", 20),
+	}
+}
+
+// newsOwner mengembalikan organisasi pemilik berita.
+func newsOwner(news *models.News) ContentOwner {
+	return ContentOwner{BEMID: news.BEMID, AssociationID: news.AssociationID, DepartmentID: news.DepartmentID}
+}
+
+// claimNewsOwner memastikan author boleh menyimpan berita atas nama pemiliknya
+// dan mengisi pemilik berita jika pengurus tidak memilihnya.
+func claimNewsOwner(news *models.News, author *Author) error {
+	owner, err := author.ClaimOwner(newsOwner(news))
+	if err != nil {
+		return err
 	}
+	news.BEMID, news.AssociationID, news.DepartmentID = owner.BEMID, owner.AssociationID, owner.DepartmentID
+	return nil
 }
 
-// CreateNews membuat berita baru.
-func (s *NewsService) CreateNews(news *models.News) error {
+// CreateNews membuat berita baru. Pengurus organisasi hanya dapat membuat berita untuk organisasinya sendiri.
+func (s *NewsService) CreateNews(news *models.News, author *Author) error {
 	if news.Title == "" || news.Content == "" {
 		return errors.New("judul dan konten tidak boleh kosong")
 	}
-	return s.repository.Create(news)
+	if err := claimNewsOwner(news, author); err != nil {
+		return err
+	}
+	if err := applyNewsSchedule(news, nil, time.Now()); err != nil {
+		return err
+	}
+	var err error
+	if news.Tags, err = s.tags.ResolveTags(news.Tags); err != nil {
+		return err
+	}
+	// Slug dibuat ulang jika berita lain yang dibuat bersamaan lebih dulu memakai slug yang sama
+	return s.slugs.CreateWithSlug(models.SlugEntityNews, "berita", news.Title, s.repository.SlugExists, func(slug string) error {
+		news.Slug = slug
+		return s.repository.Create(news)
+	})
 }
 
-// UpdateNews memperbarui berita yang ada.
-func (s *NewsService) UpdateNews(news *models.News) error {
-	return s.repository.Update(news)
+// UpdateNews memperbarui berita yang ada beserta seluruh tagnya.
+// Slug dibuat ulang hanya jika judul berubah, dan slug lama tetap dialihkan ke berita ini.
+// Pengurus organisasi hanya dapat mengubah berita milik organisasinya sendiri.
+func (s *NewsService) UpdateNews(news *models.News, author *Author) error {
+	stored, err := s.repository.FindByID(news.ID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errors.New("berita tidak ditemukan")
+		}
+		return err
+	}
+	if err := applyNewsSchedule(news, stored, time.Now()); err != nil {
+		return err
+	}
+	if err := author.Authorize(newsOwner(stored)); err != nil {
+		return err
+	}
+	if err := claimNewsOwner(news, author); err != nil {
+		return err
+	}
+	news.Slug = stored.Slug
+	if stored.Title != news.Title || stored.Slug == "" {
+		slug, err := s.slugs.Assign(models.SlugEntityNews, "berita", news.ID, news.Title, stored.Slug, s.repository.SlugExists)
+		if err != nil {
+			return err
+		}
+		news.Slug = slug
+	}
+	if news.Tags, err = s.tags.ResolveTags(news.Tags); err != nil {
+		return err
+	}
+	if err := s.repository.Update(news); err != nil {
+		return err
+	}
+	return s.tags.ReplaceTags(news, news.Tags)
+}
+
+// applyNewsSchedule memvalidasi status publikasi dan waktu terbit berita.
+// Berita tanpa status dianggap langsung terbit, berita terjadwal wajib memiliki
+// waktu terbit di masa depan, dan berita yang diterbitkan sekarang mendapat waktu terbit saat ini.
+// stored adalah berita sebelum diubah (nil saat membuat berita); berita yang sudah terbit
+// mempertahankan waktu terbitnya, atau waktu dibuat untuk berita lama tanpa waktu terbit.
+func applyNewsSchedule(news, stored *models.News, now time.Time) error {
+	if news.Status == "" {
+		news.Status = models.NewsStatusPublished
+	}
+	if !newsStatuses[news.Status] {
+		return errors.New("status berita tidak valid")
+	}
+
+	switch news.Status {
+	case models.NewsStatusScheduled:
+		if news.PublishAt == nil {
+			return errors.New("waktu terbit wajib diisi untuk berita terjadwal")
+		}
+		if !news.PublishAt.After(now) {
+			return errors.New("waktu terbit berita terjadwal harus di masa depan")
+		}
+	case models.NewsStatusPublished:
+		if stored != nil && stored.Status == models.NewsStatusPublished {
+			news.PublishAt = stored.PublishAt
+			if news.PublishAt == nil {
+				createdAt := stored.CreatedAt
+				news.PublishAt = &createdAt
+			}
+		} else if news.PublishAt == nil || news.PublishAt.After(now) {
+			news.PublishAt = &now
+		}
+	}
+	return nil
 }
 
 // GetNewsByID mendapatkan berita berdasarkan ID.
@@ -45,20 +167,141 @@ func (s *NewsService) GetNewsByID(id uint) (*models.News, error) {
 	return news, nil
 }
 
-// GetAllNews mendapatkan semua berita dengan pagination.
-func (s *NewsService) GetAllNews(limit, offset int) ([]models.News, int64, error) {
-	return s.repository.GetAllNews(limit, offset)
+// GetAllNews mendapatkan semua berita sesuai filter dengan pagination, termasuk draf dan berita terjadwal.
+func (s *NewsService) GetAllNews(filter repositories.NewsFilter, limit, offset int) ([]models.News, int64, error) {
+	if filter.Status != "" && !newsStatuses[filter.Status] {
+		return nil, 0, errors.New("status berita tidak valid")
+	}
+	filter.Tag = utils.Slugify(filter.Tag)
+	return s.repository.GetAllNews(filter, limit, offset)
+}
+
+// GetManagedNews mendapatkan berita yang dapat dikelola author, termasuk draf dan berita terjadwal.
+// Admin mendapatkan semua berita, pengurus hanya berita milik organisasinya.
+func (s *NewsService) GetManagedNews(author *Author, filter repositories.NewsFilter, limit, offset int) ([]models.News, int64, error) {
+	if !author.IsAdmin {
+		if !author.IsOfficer() {
+			return nil, 0, ErrNotOfficer
+		}
+		filter.Owners = &repositories.OwnerScope{BEMID: author.BEMID, OrganizationIDs: author.OrganizationIDs()}
+	}
+	return s.GetAllNews(filter, limit, offset)
 }
 
-// DeleteNews menghapus sebuah berita.
-func (s *NewsService) DeleteNews(id uint) error {
-	_, err := s.repository.FindByID(id)
+// GetManagedNewsByID mendapatkan berita berdasarkan ID jika author boleh mengelolanya.
+func (s *NewsService) GetManagedNewsByID(id uint, author *Author) (*models.News, error) {
+	news, err := s.GetNewsByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := author.Authorize(newsOwner(news)); err != nil {
+		return nil, err
+	}
+	return news, nil
+}
+
+// GetPublishedNews mendapatkan berita yang sudah terbit sesuai filter dengan pagination.
+func (s *NewsService) GetPublishedNews(filter repositories.NewsFilter, limit, offset int) ([]models.News, int64, error) {
+	filter.Status = ""
+	filter.Tag = utils.Slugify(filter.Tag)
+	return s.repository.GetPublishedNews(filter, limit, offset)
+}
+
+// GetPublishedNewsByID mendapatkan berita yang sudah terbit berdasarkan ID.
+// Draf dan berita terjadwal dianggap tidak ada.
+func (s *NewsService) GetPublishedNewsByID(id uint) (*models.News, error) {
+	news, err := s.repository.FindPublishedByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("berita tidak ditemukan")
+		}
+		return nil, err
+	}
+	return news, nil
+}
+
+// GetPublishedNewsBySlug mendapatkan berita yang sudah terbit berdasarkan slug.
+// Slug lama dari judul sebelumnya tetap menemukan beritanya; pemanggil dapat
+// membandingkan slug berita yang dikembalikan untuk mengalihkan ke slug terbaru.
+func (s *NewsService) GetPublishedNewsBySlug(slug string) (*models.News, error) {
+	news, err := s.repository.FindPublishedBySlug(slug)
+	if err == nil {
+		return news, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	id, err := s.slugs.Resolve(models.SlugEntityNews, slug)
+	if err != nil {
+		return nil, err
+	}
+	if id == 0 {
+		return nil, errors.New("berita tidak ditemukan")
+	}
+	return s.GetPublishedNewsByID(id)
+}
+
+// BackfillSlugs membuat slug untuk berita yang dibuat sebelum slug tersedia.
+func (s *NewsService) BackfillSlugs() error {
+	newsList, err := s.repository.FindWithoutSlug()
+	if err != nil {
+		return err
+	}
+	for _, news := range newsList {
+		slug, err := s.slugs.Assign(models.SlugEntityNews, "berita", news.ID, news.Title, "", s.repository.SlugExists)
+		if err != nil {
+			return err
+		}
+		if err := s.repository.UpdateSlug(news.ID, slug); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PublishDueNews menerbitkan berita terjadwal yang waktu terbitnya sudah lewat.
+func (s *NewsService) PublishDueNews() (int64, error) {
+	return s.repository.PublishDue(time.Now())
+}
+
+// defaultNewsPublishInterval adalah interval publisher jika interval yang dikonfigurasi tidak valid.
+const defaultNewsPublishInterval = time.Minute
+
+// StartScheduledPublisher menjalankan publisher latar belakang yang menerbitkan
+// berita terjadwal setiap interval. Interval nol atau negatif diganti interval bawaan.
+func (s *NewsService) StartScheduledPublisher(interval time.Duration) {
+	if interval <= 0 {
+		log.Printf("Invalid news publish interval %v, using %v", interval, defaultNewsPublishInterval)
+		interval = defaultNewsPublishInterval
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			published, err := s.PublishDueNews()
+			if err != nil {
+				log.Printf("Failed to publish scheduled news: %v", err)
+			} else if published > 0 {
+				log.Printf("Published %d scheduled news", published)
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// DeleteNews menghapus sebuah berita. Pengurus organisasi hanya dapat menghapus berita organisasinya.
+func (s *NewsService) DeleteNews(id uint, author *Author) error {
+	news, err := s.repository.FindByID(id)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return errors.New("berita yang akan dihapus tidak ditemukan")
 		}
 		return err
 	}
+	if err := author.Authorize(newsOwner(news)); err != nil {
+		return err
+	}
 	return s.repository.DeleteByID(id)
 }
 
@@ -73,3 +316,100 @@ func (s *NewsService) RestoreNews(id uint) (*models.News, error) {
 	}
 	return restoredNews, nil
 }
+
+// htmlTagPattern mencocokkan tag HTML pada konten berita.
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// newsSummaryLength adalah panjang maksimum ringkasan berita pada feed.
+const newsSummaryLength = 280
+
+// newsSummary membuat ringkasan teks polos dari konten berita.
+func newsSummary(content string) string {
+	text := html.UnescapeString(htmlTagPattern.ReplaceAllString(content, " "))
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= newsSummaryLength {
+		return text
+	}
+	runes := []rune(text)
+	return strings.TrimSpace(string(runes[:newsSummaryLength])) + "…"
+}
+
+// newsPublishedAt mengembalikan waktu terbit berita. Berita lama memakai waktu pembuatannya.
+func newsPublishedAt(news *models.News) time.Time {
+	if news.PublishAt != nil {
+		return *news.PublishAt
+	}
+	return news.CreatedAt
+}
+
+// tagNames mengembalikan nama-nama tag.
+func tagNames(tags []models.Tag) []string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return names
+}
+
+// newsFeed menyusun kanal dan item feed dari berita terbaru yang sudah terbit.
+func (s *NewsService) newsFeed(filter repositories.NewsFilter, selfPath string) (utils.FeedChannel, []utils.FeedItem, error) {
+	newsList, _, err := s.GetPublishedNews(filter, s.feedLimit, 0)
+	if err != nil {
+		return utils.FeedChannel{}, nil, err
+	}
+
+	channel := utils.FeedChannel{
+		Title:       "Berita BEM IT Del",
+		Description: "Berita terbaru dari BEM, himpunan, dan departemen IT Del",
+		Link:        s.siteURL + "/news",
+		SelfLink:    s.baseURL + selfPath,
+		Language:    "id",
+		Updated:     time.Now(),
+	}
+	if len(newsList) > 0 {
+		channel.Updated = newsList[0].UpdatedAt
+	}
+
+	items := make([]utils.FeedItem, 0, len(newsList))
+	for i := range newsList {
+		news := &newsList[i]
+		// ID memakai nomor berita agar tidak berubah saat judul dan slug diganti
+		id := fmt.Sprintf("%s/news/%d", s.siteURL, news.ID)
+		link := id
+		if news.Slug != "" {
+			link = fmt.Sprintf("%s/news/%s", s.siteURL, news.Slug)
+		}
+		if news.UpdatedAt.After(channel.Updated) {
+			channel.Updated = news.UpdatedAt
+		}
+		items = append(items, utils.FeedItem{
+			ID:         id,
+			Title:      news.Title,
+			Link:       link,
+			Summary:    newsSummary(news.Content),
+			Content:    news.Content,
+			Categories: tagNames(news.Tags),
+			Published:  newsPublishedAt(news),
+			Updated:    news.UpdatedAt,
+		})
+	}
+	return channel, items, nil
+}
+
+// BuildRSSFeed membangun feed RSS 2.0 berita yang sudah terbit sesuai filter.
+func (s *NewsService) BuildRSSFeed(filter repositories.NewsFilter, selfPath string) ([]byte, error) {
+	channel, items, err := s.newsFeed(filter, selfPath)
+	if err != nil {
+		return nil, err
+	}
+	return utils.BuildRSS(channel, items)
+}
+
+// BuildAtomFeed membangun feed Atom berita yang sudah terbit sesuai filter.
+func (s *NewsService) BuildAtomFeed(filter repositories.NewsFilter, selfPath string) ([]byte, error) {
+	channel, items, err := s.newsFeed(filter, selfPath)
+	if err != nil {
+		return nil, err
+	}
+	return utils.BuildAtom(channel, items)
+}
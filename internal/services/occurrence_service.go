@@ -0,0 +1,366 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"errors"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxOccurrences membatasi jumlah pertemuan yang dibentuk dari satu aturan pengulangan.
+const maxOccurrences = 500
+
+var (
+	// ErrNotRecurring dikembalikan jika pertemuan diubah pada kegiatan yang tidak berulang
+	ErrNotRecurring = errors.New("kegiatan ini bukan kegiatan berulang")
+	// ErrOccurrenceNotFound dikembalikan jika waktu mulai tidak sesuai dengan aturan pengulangan
+	ErrOccurrenceNotFound = errors.New("pertemuan tidak ditemukan pada rangkaian kegiatan")
+)
+
+// Occurrence adalah satu pertemuan kegiatan setelah aturan pengulangan dan perubahannya diterapkan.
+// OccurrenceStart adalah waktu mulai menurut aturan dan menjadi penanda pertemuan tersebut.
+type Occurrence struct {
+	ActivityID      uint      `json:"activity_id"`
+	OccurrenceStart time.Time `json:"occurrence_start"`
+	Title           string    `json:"title"`
+	Description     string    `json:"description"`
+	Location        string    `json:"location"`
+	StartDate       time.Time `json:"start_date"`
+	EndDate         time.Time `json:"end_date"`
+	DepartmentID    *uint     `json:"department_id,omitempty"`
+	AssociationID   *uint     `json:"association_id,omitempty"`
+	BEMID           *uint     `json:"bem_id,omitempty"`
+	Recurring       bool      `json:"recurring"`
+	Modified        bool      `json:"modified"`
+	Cancelled       bool      `json:"cancelled"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// OccurrenceInput berisi perubahan untuk satu pertemuan; field kosong mengikuti rangkaian.
+type OccurrenceInput struct {
+	Title       string
+	Description string
+	Location    string
+	StartDate   *time.Time
+	EndDate     *time.Time
+}
+
+// validateRecurrence memeriksa aturan pengulangan dan menghitung akhir rangkaian kegiatan.
+func validateRecurrence(activity *models.Activity) error {
+	switch activity.RecurrenceFrequency {
+	case "":
+		activity.RecurrenceInterval = 1
+		activity.RecurrenceUntil = nil
+		activity.RecurrenceCount = 0
+		activity.SeriesEndDate = nil
+		return nil
+	case models.RecurrenceWeekly, models.RecurrenceMonthly:
+	default:
+		return errors.New("frekuensi pengulangan harus weekly atau monthly")
+	}
+
+	if activity.RecurrenceInterval < 1 {
+		activity.RecurrenceInterval = 1
+	}
+	if activity.RecurrenceCount < 0 {
+		return errors.New("jumlah pengulangan tidak valid")
+	}
+	if activity.RecurrenceUntil == nil && activity.RecurrenceCount == 0 {
+		return errors.New("kegiatan berulang wajib memiliki tanggal akhir atau jumlah pengulangan")
+	}
+	if activity.RecurrenceUntil != nil {
+		until := *activity.RecurrenceUntil
+		// Tanggal akhir tanpa jam berlaku sampai akhir hari tersebut
+		if until.Hour() == 0 && until.Minute() == 0 && until.Second() == 0 {
+			until = until.Add(24*time.Hour - time.Second)
+			activity.RecurrenceUntil = &until
+		}
+		if until.Before(activity.StartDate) {
+			return errors.New("tanggal akhir pengulangan tidak boleh sebelum tanggal mulai")
+		}
+	}
+
+	starts := seriesStarts(activity)
+	end := starts[len(starts)-1].Add(activity.EndDate.Sub(activity.StartDate))
+	activity.SeriesEndDate = &end
+	return nil
+}
+
+// seriesStarts menghasilkan waktu mulai setiap pertemuan menurut aturan pengulangan.
+// Pengulangan bulanan melewati bulan yang tidak memiliki tanggal yang sama (misalnya 31).
+func seriesStarts(activity *models.Activity) []time.Time {
+	if activity.RecurrenceFrequency == "" {
+		return []time.Time{activity.StartDate}
+	}
+
+	interval := activity.RecurrenceInterval
+	if interval < 1 {
+		interval = 1
+	}
+
+	starts := []time.Time{}
+	for i := 0; i < maxOccurrences*2 && len(starts) < maxOccurrences; i++ {
+		var start time.Time
+		if activity.RecurrenceFrequency == models.RecurrenceMonthly {
+			start = activity.StartDate.AddDate(0, interval*i, 0)
+			if start.Day() != activity.StartDate.Day() {
+				continue
+			}
+		} else {
+			start = activity.StartDate.AddDate(0, 0, 7*interval*i)
+		}
+
+		if activity.RecurrenceUntil != nil && start.After(*activity.RecurrenceUntil) {
+			break
+		}
+		starts = append(starts, start)
+		if activity.RecurrenceCount > 0 && len(starts) >= activity.RecurrenceCount {
+			break
+		}
+	}
+	return starts
+}
+
+// seriesEndDate mengembalikan akhir kegiatan, atau akhir pertemuan terakhir untuk kegiatan berulang.
+func seriesEndDate(activity *models.Activity) time.Time {
+	if activity.SeriesEndDate != nil {
+		return *activity.SeriesEndDate
+	}
+	return activity.EndDate
+}
+
+// expandActivity membentuk semua pertemuan kegiatan dan menerapkan perubahan per pertemuan.
+func expandActivity(activity *models.Activity, overrides []models.ActivityOccurrence) []Occurrence {
+	byStart := make(map[int64]models.ActivityOccurrence, len(overrides))
+	for _, override := range overrides {
+		if override.ActivityID == activity.ID {
+			byStart[override.OccurrenceStart.Unix()] = override
+		}
+	}
+
+	duration := activity.EndDate.Sub(activity.StartDate)
+	starts := seriesStarts(activity)
+	occurrences := make([]Occurrence, 0, len(starts))
+	for _, start := range starts {
+		occurrence := Occurrence{
+			ActivityID:      activity.ID,
+			OccurrenceStart: start,
+			Title:           activity.Title,
+			Description:     activity.Description,
+			Location:        activity.Location,
+			StartDate:       start,
+			EndDate:         start.Add(duration),
+			DepartmentID:    activity.DepartmentID,
+			AssociationID:   activity.AssociationID,
+			BEMID:           activity.BEMID,
+			Recurring:       activity.RecurrenceFrequency != "",
+			UpdatedAt:       activity.UpdatedAt,
+		}
+		if override, ok := byStart[start.Unix()]; ok {
+			applyOverride(&occurrence, override)
+		}
+		occurrences = append(occurrences, occurrence)
+	}
+	return occurrences
+}
+
+// applyOverride menerapkan perubahan satu pertemuan ke hasil pengulangan.
+func applyOverride(occurrence *Occurrence, override models.ActivityOccurrence) {
+	occurrence.Modified = true
+	occurrence.Cancelled = override.Cancelled
+	if override.Title != "" {
+		occurrence.Title = override.Title
+	}
+	if override.Description != "" {
+		occurrence.Description = override.Description
+	}
+	if override.Location != "" {
+		occurrence.Location = override.Location
+	}
+	if override.StartDate != nil {
+		occurrence.StartDate = *override.StartDate
+	}
+	if override.EndDate != nil {
+		occurrence.EndDate = *override.EndDate
+	}
+	if override.UpdatedAt.After(occurrence.UpdatedAt) {
+		occurrence.UpdatedAt = override.UpdatedAt
+	}
+}
+
+// occurrencesInRange menyaring pertemuan yang waktunya beririsan dengan from..to.
+func occurrencesInRange(occurrences []Occurrence, from, to *time.Time) []Occurrence {
+	result := make([]Occurrence, 0, len(occurrences))
+	for _, occurrence := range occurrences {
+		if from != nil && occurrence.EndDate.Before(*from) {
+			continue
+		}
+		if to != nil && occurrence.StartDate.After(*to) {
+			continue
+		}
+		result = append(result, occurrence)
+	}
+	return result
+}
+
+// OccurrenceService adalah service untuk pertemuan kegiatan berulang.
+type OccurrenceService struct {
+	repository   *repositories.OccurrenceRepository
+	activityRepo *repositories.ActivityRepository
+	activities   *ActivityService
+}
+
+// NewOccurrenceService membuat service pertemuan kegiatan baru.
+func NewOccurrenceService(db *gorm.DB) *OccurrenceService {
+	return &OccurrenceService{
+		repository:   repositories.NewOccurrenceRepository(),
+		activityRepo: repositories.NewActivityRepository(),
+		activities:   NewActivityService(db),
+	}
+}
+
+// GetOccurrences mendapatkan semua pertemuan kegiatan sesuai filter, terurut berdasarkan waktu mulai.
+func (s *OccurrenceService) GetOccurrences(filter repositories.ActivityFilter) ([]Occurrence, error) {
+	activities, err := s.activityRepo.FindAll(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uint, 0, len(activities))
+	for _, activity := range activities {
+		ids = append(ids, activity.ID)
+	}
+	overrides, err := s.repository.GetByActivityIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := []Occurrence{}
+	for i := range activities {
+		expanded := expandActivity(&activities[i], overrides)
+		occurrences = append(occurrences, occurrencesInRange(expanded, filter.From, filter.To)...)
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].StartDate.Before(occurrences[j].StartDate)
+	})
+	return occurrences, nil
+}
+
+// GetActivityOccurrences mendapatkan pertemuan sebuah kegiatan dalam rentang from..to.
+func (s *OccurrenceService) GetActivityOccurrences(activityID uint, from, to *time.Time) ([]Occurrence, error) {
+	activity, err := s.activities.GetActivityByID(activityID)
+	if err != nil {
+		return nil, err
+	}
+	overrides, err := s.repository.GetByActivityIDs([]uint{activity.ID})
+	if err != nil {
+		return nil, err
+	}
+	return occurrencesInRange(expandActivity(activity, overrides), from, to), nil
+}
+
+// findOverride memastikan pertemuan ada pada rangkaian dan mengambil perubahannya (jika ada).
+func (s *OccurrenceService) findOverride(activityID uint, occurrenceStart time.Time) (*models.Activity, *models.ActivityOccurrence, error) {
+	activity, err := s.activities.GetActivityByID(activityID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if activity.RecurrenceFrequency == "" {
+		return nil, nil, ErrNotRecurring
+	}
+
+	found := false
+	for _, start := range seriesStarts(activity) {
+		if start.Equal(occurrenceStart) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, nil, ErrOccurrenceNotFound
+	}
+
+	override, err := s.repository.FindByActivityAndStart(activityID, occurrenceStart)
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, err
+		}
+		override = &models.ActivityOccurrence{
+			ActivityID:      activityID,
+			OccurrenceStart: occurrenceStart,
+		}
+	}
+	return activity, override, nil
+}
+
+// expandOne mengembalikan hasil akhir satu pertemuan setelah perubahannya diterapkan.
+func expandOne(activity *models.Activity, occurrenceStart time.Time, overrides []models.ActivityOccurrence) *Occurrence {
+	for _, occurrence := range expandActivity(activity, overrides) {
+		if occurrence.OccurrenceStart.Equal(occurrenceStart) {
+			return &occurrence
+		}
+	}
+	return nil
+}
+
+// UpdateOccurrence mengubah satu pertemuan tanpa memengaruhi pertemuan lain dalam rangkaian.
+// Bentrok jadwal diperiksa seperti saat kegiatan disimpan.
+func (s *OccurrenceService) UpdateOccurrence(activityID uint, occurrenceStart time.Time, input OccurrenceInput) (*Occurrence, []ActivityConflict, error) {
+	activity, override, err := s.findOverride(activityID, occurrenceStart)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	override.Title = input.Title
+	override.Description = input.Description
+	override.Location = normalizeLocation(input.Location)
+	override.StartDate = input.StartDate
+	override.EndDate = input.EndDate
+	override.Cancelled = false
+
+	occurrence := expandOne(activity, occurrenceStart, []models.ActivityOccurrence{*override})
+	if occurrence.EndDate.Before(occurrence.StartDate) {
+		return nil, nil, errors.New("tanggal selesai tidak boleh sebelum tanggal mulai")
+	}
+
+	conflicts, err := s.activities.occurrenceConflicts(activity.ID, []Occurrence{*occurrence})
+	if err != nil {
+		return nil, conflicts, err
+	}
+	if err := s.repository.Save(override); err != nil {
+		return nil, conflicts, err
+	}
+	return occurrence, conflicts, nil
+}
+
+// CancelOccurrence membatalkan satu pertemuan tanpa memengaruhi pertemuan lain dalam rangkaian.
+func (s *OccurrenceService) CancelOccurrence(activityID uint, occurrenceStart time.Time) (*Occurrence, error) {
+	activity, override, err := s.findOverride(activityID, occurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+
+	override.Cancelled = true
+	if err := s.repository.Save(override); err != nil {
+		return nil, err
+	}
+	return expandOne(activity, occurrenceStart, []models.ActivityOccurrence{*override}), nil
+}
+
+// RestoreOccurrence menghapus perubahan atau pembatalan sehingga pertemuan kembali mengikuti rangkaiannya.
+func (s *OccurrenceService) RestoreOccurrence(activityID uint, occurrenceStart time.Time) (*Occurrence, error) {
+	activity, override, err := s.findOverride(activityID, occurrenceStart)
+	if err != nil {
+		return nil, err
+	}
+
+	if override.ID != 0 {
+		if err := s.repository.DeleteByID(override.ID); err != nil {
+			return nil, err
+		}
+	}
+	return expandOne(activity, occurrenceStart, nil), nil
+}
@@ -0,0 +1,255 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidProposalTransition dikembalikan jika perubahan status tidak diizinkan
+	ErrInvalidProposalTransition = errors.New("perubahan status proposal tidak valid")
+	// ErrProposalReviewForbidden dikembalikan jika peninjau tidak berhak pada tahap ini
+	ErrProposalReviewForbidden = errors.New("anda tidak berhak meninjau proposal pada tahap ini")
+	// ErrProposalNotFound dikembalikan jika proposal tidak ditemukan
+	ErrProposalNotFound = errors.New("proposal tidak ditemukan")
+	// ErrActiveProposalExists dikembalikan jika kegiatan masih memiliki proposal yang diproses
+	ErrActiveProposalExists = errors.New("kegiatan ini sudah memiliki proposal yang sedang diproses")
+)
+
+// proposalTransitions memetakan status asal ke status tujuan yang diizinkan.
+var proposalTransitions = map[string][]string{
+	models.ProposalStatusSubmitted:      {models.ProposalStatusLeaderReviewed, models.ProposalStatusRejected},
+	models.ProposalStatusLeaderReviewed: {models.ProposalStatusBEMReviewed, models.ProposalStatusRejected},
+	models.ProposalStatusBEMReviewed:    {models.ProposalStatusApproved, models.ProposalStatusRejected},
+}
+
+// ProposalService adalah service untuk alur pengajuan dan peninjauan proposal.
+type ProposalService struct {
+	repository   *repositories.ProposalRepository
+	activityRepo *repositories.ActivityRepository
+	studentRepo  *repositories.StudentRepository
+	periodRepo   *repositories.PeriodRepository
+	bemRepo      *repositories.BemRepository
+	reports      *ReportService
+	authors      *AuthorService
+}
+
+// NewProposalService membuat service proposal baru.
+func NewProposalService(db *gorm.DB) *ProposalService {
+	return &ProposalService{
+		repository:   repositories.NewProposalRepository(),
+		activityRepo: repositories.NewActivityRepository(),
+		studentRepo:  repositories.NewStudentRepository(),
+		periodRepo:   repositories.NewPeriodRepository(),
+		bemRepo:      repositories.NewBemRepository(),
+		reports:      NewReportService(db),
+		authors:      NewAuthorService(db),
+	}
+}
+
+// SubmitProposal mengajukan proposal baru untuk sebuah kegiatan.
+// Hanya admin atau pengurus organisasi penyelenggara yang dapat mengajukan proposal.
+func (s *ProposalService) SubmitProposal(proposal *models.Proposal, isAdmin bool) error {
+	activity, err := s.activityRepo.FindByID(proposal.ActivityID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrActivityNotFound
+		}
+		return err
+	}
+	if err := s.authors.AuthorizeActivity(activity, proposal.SubmittedByID, isAdmin); err != nil {
+		return err
+	}
+
+	// Organisasi dengan LPJ terlambat tidak boleh mengajukan proposal baru
+	overdue, err := s.reports.HasOverdueReports(activity)
+	if err != nil {
+		return err
+	}
+	if overdue {
+		return ErrOutstandingReports
+	}
+
+	// Kegiatan dikunci agar dua pengajuan bersamaan tidak sama-sama lolos pemeriksaan
+	// satu proposal aktif per kegiatan
+	return s.repository.Transaction(func(tx *repositories.ProposalRepository) error {
+		if err := tx.LockActivity(proposal.ActivityID); err != nil {
+			return err
+		}
+		active, err := tx.CountActiveByActivityID(proposal.ActivityID)
+		if err != nil {
+			return err
+		}
+		if active > 0 {
+			return ErrActiveProposalExists
+		}
+
+		proposal.Status = models.ProposalStatusSubmitted
+		return tx.Create(proposal)
+	})
+}
+
+// GetProposalByID mendapatkan proposal beserta riwayat tinjauannya.
+func (s *ProposalService) GetProposalByID(id uint) (*models.Proposal, error) {
+	proposal, err := s.repository.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProposalNotFound
+		}
+		return nil, err
+	}
+	return proposal, nil
+}
+
+// GetReadableProposal mendapatkan proposal jika user boleh membacanya.
+func (s *ProposalService) GetReadableProposal(id, userID uint, isAdmin bool) (*models.Proposal, error) {
+	proposal, err := s.GetProposalByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if proposal.Activity == nil {
+		return nil, ErrActivityNotFound
+	}
+	if err := s.authorizeReader(proposal.Activity, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return proposal, nil
+}
+
+// GetProposalsByActivity mendapatkan semua proposal milik sebuah kegiatan jika user boleh membacanya.
+func (s *ProposalService) GetProposalsByActivity(activityID, userID uint, isAdmin bool) ([]models.Proposal, error) {
+	activity, err := s.activityRepo.FindByID(activityID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrActivityNotFound
+		}
+		return nil, err
+	}
+	if err := s.authorizeReader(activity, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return s.repository.GetByActivityID(activityID)
+}
+
+// authorizeReader memastikan user boleh membaca proposal kegiatan: admin, pengurus organisasi
+// penyelenggara, atau pengurus BEM yang meninjau proposal semua organisasi.
+func (s *ProposalService) authorizeReader(activity *models.Activity, userID uint, isAdmin bool) error {
+	author, err := s.authors.ResolveAuthor(userID, isAdmin)
+	if err != nil {
+		return err
+	}
+	if author.BEMID != nil {
+		return nil
+	}
+	if err := author.Authorize(activityOwner(activity)); err != nil {
+		return ErrNotActivityOrganizer
+	}
+	return nil
+}
+
+// ReviewProposal memindahkan proposal ke status berikutnya.
+// Tahap "submitted" ditinjau oleh ketua organisasi penyelenggara,
+// tahap selanjutnya ditinjau oleh BEM (admin).
+func (s *ProposalService) ReviewProposal(id uint, toStatus, comment string, reviewerID uint, isBEMReviewer bool) (*models.Proposal, error) {
+	proposal, err := s.GetProposalByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canTransition(proposal.Status, toStatus) {
+		return nil, ErrInvalidProposalTransition
+	}
+
+	if proposal.Status == models.ProposalStatusSubmitted {
+		isLeader, err := s.isActivityLeader(proposal.ActivityID, reviewerID)
+		if err != nil {
+			return nil, err
+		}
+		if !isLeader {
+			return nil, ErrProposalReviewForbidden
+		}
+	} else if !isBEMReviewer {
+		return nil, ErrProposalReviewForbidden
+	}
+
+	review := &models.ProposalReview{
+		ProposalID: proposal.ID,
+		FromStatus: proposal.Status,
+		ToStatus:   toStatus,
+		ReviewerID: reviewerID,
+		Comment:    comment,
+	}
+	proposal.Status = toStatus
+
+	saved, err := s.repository.SaveTransition(proposal, review)
+	if err != nil {
+		return nil, err
+	}
+	if !saved {
+		// Proposal sudah ditinjau peninjau lain sejak dibaca
+		return nil, ErrInvalidProposalTransition
+	}
+	return s.repository.FindByID(proposal.ID)
+}
+
+// canTransition memeriksa apakah perpindahan status proposal diizinkan.
+func canTransition(from, to string) bool {
+	for _, allowed := range proposalTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// isActivityLeader memeriksa apakah user adalah ketua penyelenggara kegiatan.
+// Period menyimpan user_id kampus sebagai LeaderID (lihat AssignToPeriod),
+// sedangkan BEM menyimpan ID mahasiswa.
+func (s *ProposalService) isActivityLeader(activityID uint, userID uint) (bool, error) {
+	activity, err := s.activityRepo.FindByID(activityID)
+	if err != nil {
+		return false, err
+	}
+
+	student, err := s.studentRepo.FindByUserID(int(userID))
+	if err != nil {
+		return false, err
+	}
+	if student == nil {
+		return false, nil
+	}
+
+	var orgID *uint
+	if activity.AssociationID != nil {
+		orgID = activity.AssociationID
+	} else if activity.DepartmentID != nil {
+		orgID = activity.DepartmentID
+	}
+
+	if orgID != nil {
+		period, err := s.periodRepo.FindCurrentByOrganizationID(int(*orgID))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+		return period.LeaderID != 0 && period.LeaderID == uint(student.UserID), nil
+	}
+
+	if activity.BEMID != nil {
+		bem, err := s.bemRepo.FindByID(*activity.BEMID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return false, nil
+			}
+			return false, err
+		}
+		return bem.LeaderID != 0 && bem.LeaderID == student.ID, nil
+	}
+
+	return false, nil
+}
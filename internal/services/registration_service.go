@@ -0,0 +1,207 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// RegistrationService adalah service untuk pendaftaran (RSVP) kegiatan beserta daftar tunggunya.
+type RegistrationService struct {
+	repository   *repositories.RegistrationRepository
+	studentRepo  *repositories.StudentRepository
+	activityRepo *repositories.ActivityRepository
+	authors      *AuthorService
+}
+
+// NewRegistrationService membuat service pendaftaran kegiatan baru.
+func NewRegistrationService(db *gorm.DB) *RegistrationService {
+	return &RegistrationService{
+		repository:   repositories.NewRegistrationRepository(),
+		studentRepo:  repositories.NewStudentRepository(),
+		activityRepo: repositories.NewActivityRepository(),
+		authors:      NewAuthorService(db),
+	}
+}
+
+// studentByUserID mencari data mahasiswa pemilik token.
+func (s *RegistrationService) studentByUserID(userID uint) (*models.Student, error) {
+	student, err := s.studentRepo.FindByUserID(int(userID))
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, errors.New("data mahasiswa tidak ditemukan")
+	}
+	return student, nil
+}
+
+// Register mendaftarkan mahasiswa ke kegiatan. Jika kuota penuh, pendaftaran masuk daftar tunggu.
+func (s *RegistrationService) Register(activityID, userID uint) (*models.ActivityRegistration, error) {
+	student, err := s.studentByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var registration *models.ActivityRegistration
+	err = s.repository.Transaction(func(tx *repositories.RegistrationRepository) error {
+		activity, err := tx.LockActivity(activityID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrActivityNotFound
+			}
+			return err
+		}
+		if time.Now().After(seriesEndDate(activity)) {
+			return errors.New("pendaftaran kegiatan sudah ditutup")
+		}
+
+		existing, err := tx.FindByActivityAndStudent(activityID, student.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if existing != nil && existing.Status != models.RegistrationCancelled {
+			return errors.New("anda sudah terdaftar pada kegiatan ini")
+		}
+
+		registered, err := tx.CountByStatus(activityID, models.RegistrationRegistered)
+		if err != nil {
+			return err
+		}
+
+		status := models.RegistrationRegistered
+		if activity.Capacity > 0 && registered >= int64(activity.Capacity) {
+			status = models.RegistrationWaitlisted
+		}
+
+		registration = existing
+		if registration == nil {
+			registration = &models.ActivityRegistration{
+				ActivityID: activityID,
+				StudentID:  student.ID,
+			}
+		}
+		registration.Status = status
+		registration.RegisteredAt = time.Now()
+		registration.PromotedAt = nil
+		registration.CancelledAt = nil
+		return tx.Save(registration)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return registration, nil
+}
+
+// Cancel membatalkan pendaftaran mahasiswa dan menaikkan pendaftar pertama dari daftar tunggu.
+func (s *RegistrationService) Cancel(activityID, userID uint) (*models.ActivityRegistration, error) {
+	student, err := s.studentByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var registration *models.ActivityRegistration
+	err = s.repository.Transaction(func(tx *repositories.RegistrationRepository) error {
+		activity, err := tx.LockActivity(activityID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrActivityNotFound
+			}
+			return err
+		}
+
+		registration, err = tx.FindByActivityAndStudent(activityID, student.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return errors.New("anda belum terdaftar pada kegiatan ini")
+			}
+			return err
+		}
+		if registration.Status == models.RegistrationCancelled {
+			return errors.New("pendaftaran sudah dibatalkan")
+		}
+
+		now := time.Now()
+		registration.Status = models.RegistrationCancelled
+		registration.CancelledAt = &now
+		if err := tx.Save(registration); err != nil {
+			return err
+		}
+
+		return fillFromWaitlist(tx, activity)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return registration, nil
+}
+
+// FillFromWaitlist menaikkan pendaftar daftar tunggu selama kuota kegiatan masih tersedia,
+// misalnya setelah penyelenggara menambah kapasitas.
+func (s *RegistrationService) FillFromWaitlist(activityID uint) error {
+	return s.repository.Transaction(func(tx *repositories.RegistrationRepository) error {
+		activity, err := tx.LockActivity(activityID)
+		if err != nil {
+			return err
+		}
+		return fillFromWaitlist(tx, activity)
+	})
+}
+
+// fillFromWaitlist memindahkan pendaftar daftar tunggu ke status terdaftar sesuai sisa kuota.
+func fillFromWaitlist(tx *repositories.RegistrationRepository, activity *models.Activity) error {
+	for {
+		if activity.Capacity > 0 {
+			registered, err := tx.CountByStatus(activity.ID, models.RegistrationRegistered)
+			if err != nil {
+				return err
+			}
+			if registered >= int64(activity.Capacity) {
+				return nil
+			}
+		}
+
+		next, err := tx.FirstWaitlisted(activity.ID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		now := time.Now()
+		next.Status = models.RegistrationRegistered
+		next.PromotedAt = &now
+		if err := tx.Save(next); err != nil {
+			return err
+		}
+	}
+}
+
+// GetRegistrants mendapatkan daftar pendaftar kegiatan, opsional per status.
+// Hanya admin dan pengurus penyelenggara kegiatan yang boleh melihat data pendaftar.
+func (s *RegistrationService) GetRegistrants(activityID uint, status string, userID uint, isAdmin bool) ([]models.ActivityRegistration, error) {
+	activity, err := s.activityRepo.FindByID(activityID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrActivityNotFound
+		}
+		return nil, err
+	}
+	if err := s.authors.AuthorizeActivity(activity, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return s.repository.GetByActivityID(activityID, status)
+}
+
+// GetMyRegistrations mendapatkan semua pendaftaran milik mahasiswa pemilik token.
+func (s *RegistrationService) GetMyRegistrations(userID uint) ([]models.ActivityRegistration, error) {
+	student, err := s.studentByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repository.GetByStudentID(student.ID)
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"bem_be/internal/utils"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrOutstandingReports dikembalikan jika penyelenggara masih memiliki LPJ yang terlambat
+	ErrOutstandingReports = errors.New("organisasi masih memiliki LPJ yang melewati batas waktu")
+	// ErrReportNotFound dikembalikan jika LPJ kegiatan belum dikumpulkan
+	ErrReportNotFound = errors.New("LPJ untuk kegiatan ini belum dikumpulkan")
+)
+
+// Jenis penyelenggara kegiatan
+const (
+	OrganizerAssociation = "association"
+	OrganizerDepartment  = "department"
+	OrganizerBEM         = "bem"
+)
+
+// ReportService adalah service untuk laporan pertanggungjawaban (LPJ) kegiatan.
+type ReportService struct {
+	repository   *repositories.ReportRepository
+	activityRepo *repositories.ActivityRepository
+	authors      *AuthorService
+	deadlineDays int
+}
+
+// NewReportService membuat service LPJ baru.
+// Batas waktu LPJ dihitung dari akhir kegiatan (atau akhir rangkaian kegiatan berulang)
+// dan diatur lewat LPJ_DEADLINE_DAYS.
+func NewReportService(db *gorm.DB) *ReportService {
+	return &ReportService{
+		repository:   repositories.NewReportRepository(),
+		activityRepo: repositories.NewActivityRepository(),
+		authors:      NewAuthorService(db),
+		deadlineDays: utils.GetEnvAsInt("LPJ_DEADLINE_DAYS", 14),
+	}
+}
+
+// OverdueActivity adalah kegiatan yang LPJ-nya belum dikumpulkan setelah batas waktu.
+type OverdueActivity struct {
+	Activity    models.Activity `json:"activity"`
+	Deadline    time.Time       `json:"deadline"`
+	DaysOverdue int             `json:"days_overdue"`
+}
+
+// OutstandingReports mengelompokkan LPJ yang terlambat per penyelenggara.
+type OutstandingReports struct {
+	OrganizerType string            `json:"organizer_type"`
+	OrganizerID   uint              `json:"organizer_id"`
+	Activities    []OverdueActivity `json:"activities"`
+}
+
+// activityOrganizer mengembalikan jenis dan ID penyelenggara kegiatan.
+func activityOrganizer(activity *models.Activity) (string, uint) {
+	switch {
+	case activity.AssociationID != nil:
+		return OrganizerAssociation, *activity.AssociationID
+	case activity.DepartmentID != nil:
+		return OrganizerDepartment, *activity.DepartmentID
+	case activity.BEMID != nil:
+		return OrganizerBEM, *activity.BEMID
+	}
+	return "", 0
+}
+
+// organizerFilter membuat filter kegiatan yang diselenggarakan oleh penyelenggara yang sama.
+func organizerFilter(activity *models.Activity) repositories.ActivityFilter {
+	return repositories.ActivityFilter{
+		DepartmentID:  activity.DepartmentID,
+		AssociationID: activity.AssociationID,
+		BEMID:         activity.BEMID,
+	}
+}
+
+// ReportDeadline menghitung batas waktu pengumpulan LPJ sebuah kegiatan.
+func (s *ReportService) ReportDeadline(activity *models.Activity) time.Time {
+	return seriesEndDate(activity).AddDate(0, 0, s.deadlineDays)
+}
+
+// SubmitReport mengunggah LPJ sebuah kegiatan. Unggahan ulang menggantikan file sebelumnya.
+// Hanya admin atau pengurus organisasi penyelenggara yang dapat mengunggah LPJ.
+func (s *ReportService) SubmitReport(activityID uint, filePath string, userID uint, isAdmin bool) (*models.Report, error) {
+	activity, err := s.organizerActivity(activityID, userID, isAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().Before(seriesEndDate(activity)) {
+		return nil, errors.New("LPJ hanya dapat dikumpulkan setelah kegiatan selesai")
+	}
+
+	deadline := s.ReportDeadline(activity)
+
+	report, err := s.repository.FindByActivityID(activityID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if report != nil {
+		oldPath := report.FilePath
+		report.FilePath = filePath
+		report.SubmittedByID = userID
+		report.Deadline = deadline
+		// Keterlambatan dihitung dari pengumpulan pertama agar perbaikan file tidak
+		// menjadikan LPJ yang tepat waktu terlambat
+		report.SubmittedLate = report.CreatedAt.After(deadline)
+		if err := s.repository.Update(report); err != nil {
+			return nil, err
+		}
+		if oldPath != "" && oldPath != filePath {
+			_ = os.Remove(oldPath)
+		}
+		return report, nil
+	}
+
+	report = &models.Report{
+		ActivityID:    activityID,
+		FilePath:      filePath,
+		SubmittedByID: userID,
+		Deadline:      deadline,
+		SubmittedLate: time.Now().After(deadline),
+	}
+	if err := s.repository.Create(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// organizerActivity mendapatkan kegiatan jika user adalah admin atau pengurus organisasi penyelenggaranya.
+func (s *ReportService) organizerActivity(activityID, userID uint, isAdmin bool) (*models.Activity, error) {
+	activity, err := s.activityRepo.FindByID(activityID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrActivityNotFound
+		}
+		return nil, err
+	}
+	if err := s.authors.AuthorizeActivity(activity, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	return activity, nil
+}
+
+// GetReportByActivity mendapatkan LPJ milik sebuah kegiatan.
+// Hanya admin atau pengurus organisasi penyelenggara yang dapat membaca LPJ.
+func (s *ReportService) GetReportByActivity(activityID, userID uint, isAdmin bool) (*models.Report, error) {
+	if _, err := s.organizerActivity(activityID, userID, isAdmin); err != nil {
+		return nil, err
+	}
+	report, err := s.repository.FindByActivityID(activityID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReportNotFound
+		}
+		return nil, err
+	}
+	return report, nil
+}
+
+// GetOutstandingReports mendapatkan LPJ yang terlambat, dikelompokkan per penyelenggara.
+func (s *ReportService) GetOutstandingReports(filter repositories.ActivityFilter) ([]OutstandingReports, error) {
+	now := time.Now()
+	cutoff := now.AddDate(0, 0, -s.deadlineDays)
+
+	activities, err := s.repository.FindOverdueActivities(cutoff, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := []OutstandingReports{}
+	index := map[string]int{}
+	for _, activity := range activities {
+		organizerType, organizerID := activityOrganizer(&activity)
+		key := fmt.Sprintf("%s:%d", organizerType, organizerID)
+
+		i, ok := index[key]
+		if !ok {
+			groups = append(groups, OutstandingReports{
+				OrganizerType: organizerType,
+				OrganizerID:   organizerID,
+				Activities:    []OverdueActivity{},
+			})
+			i = len(groups) - 1
+			index[key] = i
+		}
+
+		deadline := s.ReportDeadline(&activity)
+		groups[i].Activities = append(groups[i].Activities, OverdueActivity{
+			Activity:    activity,
+			Deadline:    deadline,
+			DaysOverdue: int(now.Sub(deadline).Hours() / 24),
+		})
+	}
+
+	return groups, nil
+}
+
+// HasOverdueReports memeriksa apakah penyelenggara kegiatan masih memiliki LPJ yang terlambat.
+func (s *ReportService) HasOverdueReports(activity *models.Activity) (bool, error) {
+	organizerType, _ := activityOrganizer(activity)
+	if organizerType == "" {
+		return false, nil
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -s.deadlineDays)
+	activities, err := s.repository.FindOverdueActivities(cutoff, organizerFilter(activity))
+	if err != nil {
+		return false, err
+	}
+	return len(activities) > 0, nil
+}
@@ -4,13 +4,45 @@ import (
 	"bem_be/internal/models"
 	"bem_be/internal/repositories"
 	"errors"
+	"strings"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrInvalidRequestTransition dikembalikan jika perubahan status permintaan tidak diizinkan
+	ErrInvalidRequestTransition = errors.New("perubahan status permintaan peminjaman tidak valid")
+	// ErrRequestNotEditable dikembalikan jika permintaan yang sudah diproses diubah atau dihapus
+	ErrRequestNotEditable = errors.New("permintaan peminjaman yang sudah diproses tidak dapat diubah")
+	// ErrRequestNotFound dikembalikan jika permintaan peminjaman tidak ditemukan
+	ErrRequestNotFound = errors.New("permintaan tidak ditemukan")
+	// ErrRequestForbidden dikembalikan jika mahasiswa mengakses permintaan organisasi lain
+	ErrRequestForbidden = errors.New("anda tidak berhak mengakses permintaan peminjaman ini")
+)
+
+// requestTransitions memetakan status asal ke status tujuan yang diizinkan.
+// Status rejected, cancelled, dan returned adalah status akhir.
+var requestTransitions = map[string][]string{
+	models.RequestStatusPending:    {models.RequestStatusApproved, models.RequestStatusRejected, models.RequestStatusCancelled},
+	models.RequestStatusApproved:   {models.RequestStatusHandedOver, models.RequestStatusCancelled},
+	models.RequestStatusHandedOver: {models.RequestStatusReturned},
+}
+
+// canTransitionRequest memeriksa apakah status permintaan boleh berpindah dari from ke to.
+func canTransitionRequest(from, to string) bool {
+	for _, allowed := range requestTransitions[strings.ToLower(from)] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
 type RequestService struct {
 	repository  *repositories.RequestRepository
 	studentRepo *repositories.StudentRepository
+	inventory   *InventoryService
 	db          *gorm.DB
 }
 
@@ -18,6 +50,7 @@ func NewRequestService(db *gorm.DB) *RequestService {
 	return &RequestService{
 		repository:  repositories.NewRequestRepository(),
 		studentRepo: repositories.NewStudentRepository(),
+		inventory:   NewInventoryService(db),
 	}
 }
 
@@ -26,18 +59,233 @@ func (s *RequestService) GetStudentByUserID(userID int) (*models.Student, error)
 }
 
 func (s *RequestService) CreateRequest(request *models.Request) error {
-	return s.repository.Create(request)
+	if err := s.inventory.CheckRequest(request); err != nil {
+		return err
+	}
+	request.Status = models.RequestStatusPending
+	request.ApproverID = nil
+	if err := s.repository.Create(request); err != nil {
+		return err
+	}
+	organizations, err := s.overdueOrganizations()
+	if err != nil {
+		return err
+	}
+	request.OrganizationOverdue = organizations[request.OrganizationID]
+	return nil
 }
 
+// UpdateRequest mengubah permintaan yang masih pending. Permintaan dikunci selama perubahan
+// agar persetujuan atau pembatalan bersamaan tidak tertimpa status pending yang lama.
 func (s *RequestService) UpdateRequest(request *models.Request) error {
-	existingRequest, err := s.repository.FindByID(request.ID)
+	return s.repository.Transaction(func(tx *repositories.RequestRepository) error {
+		existingRequest, err := lockRequest(tx, request.ID)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(existingRequest.Status, models.RequestStatusPending) {
+			return ErrRequestNotEditable
+		}
+		if err := s.inventory.CheckRequestLocked(tx, request); err != nil {
+			return err
+		}
+		// Status hanya berubah melalui approve, reject, dan cancel
+		request.Status = existingRequest.Status
+		request.ApproverID = existingRequest.ApproverID
+		// Item yang sudah di-preload tidak ikut disimpan agar tidak menimpa ItemID yang baru
+		request.Item = nil
+		return tx.Update(request)
+	})
+}
+
+// lockRequest mengambil permintaan dengan row lock di dalam transaksi tx.
+func lockRequest(tx *repositories.RequestRepository, id uint) (*models.Request, error) {
+	request, err := tx.LockByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRequestNotFound
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+// findRequest mendapatkan permintaan dengan pesan error yang sesuai.
+func (s *RequestService) findRequest(id uint) (*models.Request, error) {
+	request, err := s.repository.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRequestNotFound
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+// ApproveRequest menyetujui permintaan yang masih pending dan mencatat admin yang menyetujui.
+// Sisa stok diperiksa ulang karena permintaan lain mungkin sudah disetujui sejak permintaan dibuat.
+// Pemeriksaan stok dan perubahan status berjalan dalam satu transaksi dengan baris barang dikunci,
+// sehingga dua persetujuan bersamaan tidak dapat melebihi stok.
+func (s *RequestService) ApproveRequest(id, approverID uint, reason string) (*models.Request, error) {
+	err := s.repository.Transaction(func(tx *repositories.RequestRepository) error {
+		request, err := lockRequest(tx, id)
+		if err != nil {
+			return err
+		}
+		if request.ItemID != nil && canTransitionRequest(request.Status, models.RequestStatusApproved) {
+			if err := s.inventory.CheckRequestLocked(tx, request); err != nil {
+				return err
+			}
+		}
+		return updateRequestStatus(tx, request, models.RequestStatusApproved, decision(approverID, reason))
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.findRequest(id)
+}
+
+// RejectRequest menolak permintaan yang masih pending. Alasan penolakan wajib diisi.
+func (s *RequestService) RejectRequest(id, approverID uint, reason string) (*models.Request, error) {
+	if strings.TrimSpace(reason) == "" {
+		return nil, errors.New("alasan penolakan wajib diisi")
+	}
+	return s.decide(id, approverID, models.RequestStatusRejected, reason)
+}
+
+// changeStatus memindahkan status permintaan jika transisinya diizinkan. apply mengisi
+// data tambahan perubahan status seperti siapa yang mengonfirmasi, kapan, dan catatannya.
+// Alasan keputusan, catatan penyerahan, dan kondisi pengembalian disimpan di kolom terpisah
+// agar langkah berikutnya tidak menimpa catatan langkah sebelumnya.
+// Permintaan dikunci selama perubahan agar transisi bersamaan diperiksa satu per satu.
+func (s *RequestService) changeStatus(id uint, toStatus string, apply func(request *models.Request, now time.Time)) (*models.Request, error) {
+	err := s.repository.Transaction(func(tx *repositories.RequestRepository) error {
+		request, err := lockRequest(tx, id)
+		if err != nil {
+			return err
+		}
+		return updateRequestStatus(tx, request, toStatus, apply)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.findRequest(id)
+}
+
+// updateRequestStatus memeriksa transisi lalu menyimpan status baru permintaan melalui repository.
+func updateRequestStatus(repository *repositories.RequestRepository, request *models.Request, toStatus string, apply func(request *models.Request, now time.Time)) error {
+	if !canTransitionRequest(request.Status, toStatus) {
+		return ErrInvalidRequestTransition
+	}
+
+	now := time.Now()
+	request.Status = toStatus
+	request.UpdatedAt = now
+	apply(request, now)
+	return repository.UpdateStatus(request)
+}
+
+// decision mengisi admin yang memutuskan permintaan, waktu, dan alasannya.
+func decision(approverID uint, reason string) func(request *models.Request, now time.Time) {
+	return func(request *models.Request, now time.Time) {
+		request.ApproverID = &approverID
+		request.DecidedAt = &now
+		request.StatusReason = strings.TrimSpace(reason)
+	}
+}
+
+// decide mencatat keputusan admin atas permintaan.
+func (s *RequestService) decide(id, approverID uint, toStatus, reason string) (*models.Request, error) {
+	return s.changeStatus(id, toStatus, decision(approverID, reason))
+}
+
+// CancelRequest membatalkan permintaan yang belum ditolak atau dibatalkan.
+func (s *RequestService) CancelRequest(id, userID uint, reason string) (*models.Request, error) {
+	return s.changeStatus(id, models.RequestStatusCancelled, func(request *models.Request, now time.Time) {
+		request.CancelledByID = &userID
+		request.CancelledAt = &now
+		request.StatusReason = strings.TrimSpace(reason)
+	})
+}
+
+// HandOverRequest mencatat bahwa barang permintaan yang disetujui sudah diserahkan ke peminjam.
+func (s *RequestService) HandOverRequest(id, userID uint, note string) (*models.Request, error) {
+	return s.changeStatus(id, models.RequestStatusHandedOver, func(request *models.Request, now time.Time) {
+		request.HandedOverByID = &userID
+		request.HandedOverAt = &now
+		request.HandOverNote = strings.TrimSpace(note)
+	})
+}
+
+// ReturnRequest mencatat pengembalian barang beserta catatan kondisinya. Catatan kondisi wajib diisi.
+func (s *RequestService) ReturnRequest(id, userID uint, condition string) (*models.Request, error) {
+	condition = strings.TrimSpace(condition)
+	if condition == "" {
+		return nil, errors.New("catatan kondisi barang saat dikembalikan wajib diisi")
+	}
+	return s.changeStatus(id, models.RequestStatusReturned, func(request *models.Request, now time.Time) {
+		request.ReturnedByID = &userID
+		request.ReturnedAt = &now
+		request.ReturnCondition = condition
+	})
+}
+
+// OverdueRequest adalah permintaan yang barangnya belum dikembalikan melewati rencana kembali.
+type OverdueRequest struct {
+	models.Request
+	OverdueDays int `json:"overdue_days"`
+}
+
+// GetOverdueRequests mendapatkan permintaan yang barangnya sudah diserahkan tetapi belum
+// dikembalikan melewati rencana kembali, yang paling lama terlambat lebih dulu.
+func (s *RequestService) GetOverdueRequests() ([]OverdueRequest, error) {
+	requests, err := s.repository.GetHandedOver()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	overdue := []OverdueRequest{}
+	for _, request := range requests {
+		_, end, err := requestWindow(&request)
+		if err != nil || !end.Before(now) {
+			continue
+		}
+		overdue = append(overdue, OverdueRequest{
+			Request:     request,
+			OverdueDays: int(now.Sub(end).Hours() / 24),
+		})
+	}
+	return overdue, nil
+}
+
+// overdueOrganizations mendapatkan ID organisasi yang masih memiliki pinjaman terlambat.
+func (s *RequestService) overdueOrganizations() (map[int]bool, error) {
+	overdue, err := s.GetOverdueRequests()
+	if err != nil {
+		return nil, err
+	}
+	organizations := make(map[int]bool, len(overdue))
+	for _, request := range overdue {
+		organizations[request.OrganizationID] = true
+	}
+	return organizations, nil
+}
+
+// flagOverdueOrganizations menandai permintaan yang masih berjalan dari organisasi
+// yang belum mengembalikan pinjaman terlambat.
+func (s *RequestService) flagOverdueOrganizations(requests []models.Request) error {
+	organizations, err := s.overdueOrganizations()
 	if err != nil {
 		return err
 	}
-	if existingRequest == nil {
-		return errors.New("request not found")
+	for i := range requests {
+		switch requests[i].Status {
+		case models.RequestStatusPending, models.RequestStatusApproved:
+			requests[i].OrganizationOverdue = organizations[requests[i].OrganizationID]
+		}
 	}
-	return s.repository.Update(request)
+	return nil
 }
 
 func (s *RequestService) GetRequestByID(id uint) (*models.Request, error) {
@@ -45,7 +293,14 @@ func (s *RequestService) GetRequestByID(id uint) (*models.Request, error) {
 }
 
 func (s *RequestService) GetAllRequests(limit, offset int) ([]models.Request, int64, error) {
-	return s.repository.GetAllRequests(limit, offset)
+	requests, total, err := s.repository.GetAllRequests(limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.flagOverdueOrganizations(requests); err != nil {
+		return nil, 0, err
+	}
+	return requests, total, nil
 }
 
 type RequestWithStats struct {
@@ -60,11 +315,82 @@ func (s *RequestService) GetRequestWithStats(id uint) (*RequestWithStats, error)
 	if request == nil {
 		return nil, errors.New("permintaan tidak ditemukan")
 	}
+	requests := []models.Request{*request}
+	if err := s.flagOverdueOrganizations(requests); err != nil {
+		return nil, err
+	}
 	return &RequestWithStats{
-		Request: *request,
+		Request: requests[0],
 	}, nil
 }
 
+// studentOrganization mendapatkan mahasiswa pemilik token beserta organisasinya.
+func (s *RequestService) studentOrganization(userID uint) (*models.Student, error) {
+	student, err := s.studentRepo.FindByUserID(int(userID))
+	if err != nil {
+		return nil, err
+	}
+	if student == nil {
+		return nil, errors.New("data mahasiswa tidak ditemukan")
+	}
+	if student.OrganizationID == 0 {
+		return nil, errors.New("mahasiswa belum terdaftar di organisasi manapun")
+	}
+	return student, nil
+}
+
+// GetMyOrganizationRequests mendapatkan permintaan peminjaman milik organisasi mahasiswa pemilik token.
+func (s *RequestService) GetMyOrganizationRequests(userID uint, status string, limit, offset int) ([]models.Request, int64, error) {
+	student, err := s.studentOrganization(userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	requests, total, err := s.repository.GetByOrganizationID(student.OrganizationID, strings.ToLower(status), limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := s.flagOverdueOrganizations(requests); err != nil {
+		return nil, 0, err
+	}
+	return requests, total, nil
+}
+
+// GetMyOrganizationRequest mendapatkan permintaan peminjaman jika milik organisasi mahasiswa pemilik token.
+func (s *RequestService) GetMyOrganizationRequest(id, userID uint) (*models.Request, error) {
+	student, err := s.studentOrganization(userID)
+	if err != nil {
+		return nil, err
+	}
+	request, err := s.findRequest(id)
+	if err != nil {
+		return nil, err
+	}
+	if request.OrganizationID != student.OrganizationID {
+		return nil, ErrRequestForbidden
+	}
+	requests := []models.Request{*request}
+	if err := s.flagOverdueOrganizations(requests); err != nil {
+		return nil, err
+	}
+	return &requests[0], nil
+}
+
+// UpdateMyOrganizationRequest memperbarui permintaan peminjaman milik organisasi mahasiswa pemilik token.
+func (s *RequestService) UpdateMyOrganizationRequest(request *models.Request, userID uint) error {
+	if _, err := s.GetMyOrganizationRequest(request.ID, userID); err != nil {
+		return err
+	}
+	return s.UpdateRequest(request)
+}
+
+// CancelMyOrganizationRequest membatalkan permintaan peminjaman milik organisasi mahasiswa pemilik token.
+func (s *RequestService) CancelMyOrganizationRequest(id, userID uint, reason string) (*models.Request, error) {
+	if _, err := s.GetMyOrganizationRequest(id, userID); err != nil {
+		return nil, err
+	}
+	return s.CancelRequest(id, userID, reason)
+}
+
 func (s *RequestService) DeleteRequest(id uint) error {
 	request, err := s.repository.FindByID(id)
 	if err != nil {
@@ -73,5 +399,10 @@ func (s *RequestService) DeleteRequest(id uint) error {
 	if request == nil {
 		return errors.New("request not found")
 	}
+	switch strings.ToLower(request.Status) {
+	case models.RequestStatusApproved, models.RequestStatusHandedOver, models.RequestStatusReturned:
+		// Riwayat peminjaman barang tetap disimpan
+		return ErrRequestNotEditable
+	}
 	return s.repository.DeleteByID(id)
 }
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"bem_be/internal/repositories"
+	"bem_be/internal/search"
+	"bem_be/internal/utils"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// Backend pencarian yang dapat dipilih lewat SEARCH_BACKEND.
+const (
+	SearchBackendMemory   = "memory"
+	SearchBackendDatabase = "database"
+)
+
+// minSearchQueryLength adalah panjang minimum kata kunci pencarian.
+const minSearchQueryLength = 2
+
+var (
+	// ErrSearchQueryTooShort dikembalikan jika kata kunci pencarian terlalu pendek
+	ErrSearchQueryTooShort = errors.New("kata kunci pencarian minimal 2 karakter")
+	// ErrInvalidSearchType dikembalikan jika jenis konten yang dicari tidak dikenal
+	ErrInvalidSearchType = errors.New("jenis konten pencarian tidak valid")
+)
+
+// SearchService adalah service pencarian gabungan atas berita, pengumuman, galeri,
+// organisasi, dan kegiatan.
+type SearchService struct {
+	index search.Index
+}
+
+// NewSearchService membuat service pencarian baru. Backend dipilih dari SEARCH_BACKEND;
+// secara default indeks di memori dipakai saat development dan FULLTEXT index database
+// saat GIN_MODE=release. FULLTEXT index dibuat oleh database.Initialize; jika belum ada,
+// indeks di memori dipakai.
+func NewSearchService(db *gorm.DB) *SearchService {
+	repository := repositories.NewSearchRepository()
+
+	backend := SearchBackendMemory
+	if utils.GetEnvWithDefault("GIN_MODE", "debug") == "release" {
+		backend = SearchBackendDatabase
+	}
+	backend = strings.ToLower(utils.GetEnvWithDefault("SEARCH_BACKEND", backend))
+
+	if backend == SearchBackendDatabase {
+		if repository.HasFullTextIndexes() {
+			return &SearchService{index: repository}
+		}
+		log.Printf("Warning: full-text search indexes missing, using in-memory index")
+	}
+
+	refresh := time.Duration(utils.GetEnvAsInt("SEARCH_INDEX_REFRESH_SECONDS", 60)) * time.Second
+	return &SearchService{index: search.NewMemoryIndex(repository.LoadDocuments, refresh)}
+}
+
+// parseSearchTypes memvalidasi daftar jenis konten yang dicari.
+func parseSearchTypes(types []string) ([]string, error) {
+	valid := search.TypeFilter(search.Types)
+	parsed := make([]string, 0, len(types))
+	for _, t := range types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if t == "" {
+			continue
+		}
+		if !valid[t] {
+			return nil, ErrInvalidSearchType
+		}
+		parsed = append(parsed, t)
+	}
+	return parsed, nil
+}
+
+// Search mencari konten yang cocok dengan kata kunci, diurutkan berdasarkan relevansi.
+func (s *SearchService) Search(text string, types []string, limit, offset int) (search.Result, error) {
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) < minSearchQueryLength {
+		return search.Result{}, ErrSearchQueryTooShort
+	}
+	parsedTypes, err := parseSearchTypes(types)
+	if err != nil {
+		return search.Result{}, err
+	}
+
+	return s.index.Search(search.Query{
+		Text:   text,
+		Types:  parsedTypes,
+		Limit:  limit,
+		Offset: offset,
+	})
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"bem_be/internal/repositories"
+	"bem_be/internal/utils"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// maxSlugAttempts adalah batas percobaan menyimpan item baru jika slug-nya didahului item lain.
+const maxSlugAttempts = 3
+
+// slugExistsFunc memeriksa apakah slug sudah dipakai item selain excludeID, termasuk yang sudah dihapus.
+type slugExistsFunc func(slug string, excludeID uint) (bool, error)
+
+// SlugService adalah service untuk membuat slug unik dan mengelola pengalihan slug lama.
+type SlugService struct {
+	repository *repositories.SlugRepository
+}
+
+// NewSlugService membuat service slug baru.
+func NewSlugService() *SlugService {
+	return &SlugService{
+		repository: repositories.NewSlugRepository(),
+	}
+}
+
+// Assign membuat slug unik dari judul untuk sebuah item. Slug dianggap terpakai jika
+// dimiliki item lain atau masih menjadi pengalihan item lain. Jika slug berubah,
+// slug lama dicatat sebagai pengalihan agar tautan yang sudah dibagikan tetap berfungsi.
+func (s *SlugService) Assign(entityType, fallback string, entityID uint, title, currentSlug string, exists slugExistsFunc) (string, error) {
+	slug, err := utils.UniqueSlug(title, fallback, func(candidate string) (bool, error) {
+		used, err := exists(candidate, entityID)
+		if err != nil || used {
+			return used, err
+		}
+		redirect, err := s.repository.FindRedirect(entityType, candidate)
+		if err != nil {
+			return false, err
+		}
+		return redirect != nil && redirect.EntityID != entityID, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if entityID == 0 || slug == currentSlug {
+		return slug, nil
+	}
+
+	// Slug lama milik item ini yang dipakai kembali tidak perlu dialihkan lagi
+	if err := s.repository.DeleteRedirect(entityType, slug); err != nil {
+		return "", err
+	}
+	if currentSlug != "" {
+		if err := s.repository.SaveRedirect(entityType, currentSlug, entityID); err != nil {
+			return "", err
+		}
+	}
+	return slug, nil
+}
+
+// CreateWithSlug membuat slug unik untuk item baru lalu menyimpannya dengan create. Jika item
+// lain yang dibuat bersamaan lebih dulu menyimpan slug yang sama (duplicate key), slug dibuat
+// ulang dan penyimpanan dicoba lagi.
+func (s *SlugService) CreateWithSlug(entityType, fallback, title string, exists slugExistsFunc, create func(slug string) error) error {
+	var err error
+	for attempt := 0; attempt < maxSlugAttempts; attempt++ {
+		var slug string
+		if slug, err = s.Assign(entityType, fallback, 0, title, "", exists); err != nil {
+			return err
+		}
+		if err = create(slug); !errors.Is(err, gorm.ErrDuplicatedKey) {
+			return err
+		}
+	}
+	return err
+}
+
+// Resolve mencari item yang dituju oleh slug lama. Mengembalikan 0 jika slug tidak pernah dialihkan.
+func (s *SlugService) Resolve(entityType, slug string) (uint, error) {
+	redirect, err := s.repository.FindRedirect(entityType, slug)
+	if err != nil || redirect == nil {
+		return 0, err
+	}
+	return redirect.EntityID, nil
+}
@@ -0,0 +1,209 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"bem_be/internal/utils"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+// maxTagNameLength adalah panjang maksimum nama tag.
+const maxTagNameLength = 100
+
+var (
+	// ErrTagExists dikembalikan jika nama tag sama dengan tag lain setelah dinormalisasi
+	ErrTagExists = errors.New("tag dengan nama tersebut sudah ada, gunakan gabung tag")
+	// ErrInvalidTagMerge dikembalikan jika tag digabungkan ke dirinya sendiri
+	ErrInvalidTagMerge = errors.New("tag tidak dapat digabungkan ke dirinya sendiri")
+	// ErrUnknownTag dikembalikan jika konten memakai tag yang belum dibuat admin
+	ErrUnknownTag = errors.New("tag tidak dikenal, minta admin membuat tag terlebih dahulu")
+)
+
+// TagService adalah service untuk taksonomi tag berita, galeri, dan kegiatan.
+type TagService struct {
+	repository *repositories.TagRepository
+}
+
+// NewTagService membuat service tag baru.
+func NewTagService(db *gorm.DB) *TagService {
+	return &TagService{
+		repository: repositories.NewTagRepository(),
+	}
+}
+
+// normalizeTagName merapikan nama tag dan membuat slug-nya.
+func normalizeTagName(name string) (string, string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", "", errors.New("nama tag tidak boleh kosong")
+	}
+	if utf8.RuneCountInString(name) > maxTagNameLength {
+		return "", "", errors.New("nama tag terlalu panjang")
+	}
+	slug := utils.Slugify(name)
+	if slug == "" {
+		return "", "", errors.New("nama tag harus mengandung huruf atau angka")
+	}
+	return name, slug, nil
+}
+
+// CreateTag membuat tag baru.
+func (s *TagService) CreateTag(name string) (*models.Tag, error) {
+	name, slug, err := normalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repository.FindBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, ErrTagExists
+	}
+
+	tag := &models.Tag{Name: name, Slug: slug}
+	if err := s.repository.Create(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// RenameTag mengganti nama tag. Jika nama baru sama dengan tag lain, gunakan MergeTags.
+func (s *TagService) RenameTag(id uint, name string) (*models.Tag, error) {
+	tag, err := s.GetTagByID(id)
+	if err != nil {
+		return nil, err
+	}
+	name, slug, err := normalizeTagName(name)
+	if err != nil {
+		return nil, err
+	}
+	existing, err := s.repository.FindBySlug(slug)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ID != tag.ID {
+		return nil, ErrTagExists
+	}
+
+	tag.Name = name
+	tag.Slug = slug
+	if err := s.repository.Update(tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// GetTagByID mendapatkan tag berdasarkan ID.
+func (s *TagService) GetTagByID(id uint) (*models.Tag, error) {
+	tag, err := s.repository.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("tag tidak ditemukan")
+		}
+		return nil, err
+	}
+	return tag, nil
+}
+
+// GetAllTags mendapatkan semua tag beserta jumlah pemakaiannya.
+func (s *TagService) GetAllTags(search string) ([]repositories.TagWithUsage, error) {
+	return s.repository.GetAllWithUsage(strings.TrimSpace(search))
+}
+
+// DeleteTag menghapus tag dan melepasnya dari semua konten.
+func (s *TagService) DeleteTag(id uint) error {
+	if _, err := s.GetTagByID(id); err != nil {
+		return err
+	}
+	return s.repository.DeleteByID(id)
+}
+
+// MergeTags menggabungkan tag source ke target, misalnya "Event" ke "Acara".
+// Semua konten bertag source berpindah ke target dan source dihapus.
+func (s *TagService) MergeTags(sourceID, targetID uint) (*models.Tag, error) {
+	if sourceID == targetID {
+		return nil, ErrInvalidTagMerge
+	}
+	if _, err := s.GetTagByID(sourceID); err != nil {
+		return nil, err
+	}
+	target, err := s.GetTagByID(targetID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repository.Merge(sourceID, targetID); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// ResolveTags mencocokkan tag yang dipilih untuk konten dengan tag yang sudah ada, berdasarkan
+// ID atau slug. Nama tag dicocokkan melalui slug-nya, dan nama berupa angka yang bukan slug
+// tag dianggap sebagai ID. Tag baru hanya dapat dibuat admin melalui CreateTag, sehingga tag
+// yang tidak dikenal ditolak dengan ErrUnknownTag. Tag yang sama hanya dipakai sekali.
+func (s *TagService) ResolveTags(tags []models.Tag) ([]models.Tag, error) {
+	resolved := make([]models.Tag, 0, len(tags))
+	seen := make(map[uint]bool, len(tags))
+	for _, tag := range tags {
+		existing, err := s.findExistingTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if seen[existing.ID] {
+			continue
+		}
+		seen[existing.ID] = true
+		resolved = append(resolved, *existing)
+	}
+	return resolved, nil
+}
+
+// findExistingTag mencari tag yang dirujuk berdasarkan ID, slug, atau nama.
+func (s *TagService) findExistingTag(tag models.Tag) (*models.Tag, error) {
+	ref := strings.TrimSpace(tag.Slug)
+	if ref == "" {
+		ref = strings.TrimSpace(tag.Name)
+	}
+	if ref == "" {
+		if tag.ID == 0 {
+			return nil, errors.New("nama tag tidak boleh kosong")
+		}
+		return s.findTagByID(tag.ID, strconv.FormatUint(uint64(tag.ID), 10))
+	}
+
+	existing, err := s.repository.FindBySlug(utils.Slugify(ref))
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return existing, nil
+	}
+	if id, err := strconv.ParseUint(ref, 10, 32); err == nil {
+		return s.findTagByID(uint(id), ref)
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownTag, ref)
+}
+
+// findTagByID mencari tag berdasarkan ID; ref adalah nilai yang dikirim untuk pesan error.
+func (s *TagService) findTagByID(id uint, ref string) (*models.Tag, error) {
+	existing, err := s.repository.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownTag, ref)
+		}
+		return nil, err
+	}
+	return existing, nil
+}
+
+// ReplaceTags mengganti seluruh tag milik konten owner dengan tag yang sudah di-resolve.
+func (s *TagService) ReplaceTags(owner interface{}, tags []models.Tag) error {
+	return s.repository.ReplaceTags(owner, tags)
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"bem_be/internal/models"
+	"bem_be/internal/repositories"
+	"errors"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// VenueService adalah service untuk daftar lokasi kegiatan.
+type VenueService struct {
+	repository *repositories.VenueRepository
+}
+
+// NewVenueService membuat service lokasi baru.
+func NewVenueService(db *gorm.DB) *VenueService {
+	return &VenueService{
+		repository: repositories.NewVenueRepository(),
+	}
+}
+
+// validateVenue merapikan nama lokasi dan memastikan tidak ada nama ganda.
+func (s *VenueService) validateVenue(venue *models.Venue) error {
+	venue.Name = normalizeLocation(venue.Name)
+	if venue.Name == "" {
+		return errors.New("nama lokasi tidak boleh kosong")
+	}
+
+	existing, err := s.repository.FindByName(strings.ToLower(venue.Name))
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if existing != nil && existing.ID != venue.ID {
+		return errors.New("lokasi dengan nama tersebut sudah ada")
+	}
+	return nil
+}
+
+// CreateVenue membuat lokasi baru.
+func (s *VenueService) CreateVenue(venue *models.Venue) error {
+	if err := s.validateVenue(venue); err != nil {
+		return err
+	}
+	return s.repository.Create(venue)
+}
+
+// UpdateVenue memperbarui lokasi yang ada.
+func (s *VenueService) UpdateVenue(venue *models.Venue) error {
+	if err := s.validateVenue(venue); err != nil {
+		return err
+	}
+	return s.repository.Update(venue)
+}
+
+// GetVenueByID mendapatkan lokasi berdasarkan ID.
+func (s *VenueService) GetVenueByID(id uint) (*models.Venue, error) {
+	venue, err := s.repository.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("lokasi tidak ditemukan")
+		}
+		return nil, err
+	}
+	return venue, nil
+}
+
+// GetAllVenues mendapatkan semua lokasi.
+func (s *VenueService) GetAllVenues() ([]models.Venue, error) {
+	return s.repository.GetAll()
+}
+
+// DeleteVenue menghapus sebuah lokasi.
+func (s *VenueService) DeleteVenue(id uint) error {
+	if _, err := s.GetVenueByID(id); err != nil {
+		return err
+	}
+	return s.repository.DeleteByID(id)
+}
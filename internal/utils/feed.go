@@ -0,0 +1,183 @@
+package utils
+
+import (
+	"encoding/xml"
+	"time"
+)
+
+// FeedChannel describes the feed itself
+type FeedChannel struct {
+	Title       string
+	Description string
+	// Link is the website the feed belongs to
+	Link string
+	// SelfLink is the URL the feed is served from
+	SelfLink string
+	Language string
+	Updated  time.Time
+}
+
+// FeedItem represents a single entry in an RSS or Atom feed
+type FeedItem struct {
+	// ID must stay the same for the lifetime of the entry
+	ID         string
+	Title      string
+	Link       string
+	Summary    string
+	Content    string
+	Categories []string
+	Published  time.Time
+	Updated    time.Time
+}
+
+type rssDocument struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	AtomLink      atomLink  `xml:"atom:link"`
+	Description   string    `xml:"description"`
+	Language      string    `xml:"language,omitempty"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string   `xml:"title"`
+	Link        string   `xml:"link"`
+	GUID        rssGUID  `xml:"guid"`
+	Description string   `xml:"description"`
+	Categories  []string `xml:"category"`
+	PubDate     string   `xml:"pubDate"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang    string      `xml:"xml:lang,attr,omitempty"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID         string         `xml:"id"`
+	Title      string         `xml:"title"`
+	Link       atomLink       `xml:"link"`
+	Published  string         `xml:"published"`
+	Updated    string         `xml:"updated"`
+	Summary    string         `xml:"summary,omitempty"`
+	Content    *atomContent   `xml:"content,omitempty"`
+	Categories []atomCategory `xml:"category"`
+	Author     atomAuthor     `xml:"author"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+// BuildRSS renders the items as an RSS 2.0 document
+func BuildRSS(channel FeedChannel, items []FeedItem) ([]byte, error) {
+	doc := rssDocument{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		Channel: rssChannel{
+			Title:         channel.Title,
+			Link:          channel.Link,
+			AtomLink:      atomLink{Href: channel.SelfLink, Rel: "self", Type: "application/rss+xml"},
+			Description:   channel.Description,
+			Language:      channel.Language,
+			LastBuildDate: channel.Updated.Format(time.RFC1123Z),
+			Items:         make([]rssItem, 0, len(items)),
+		},
+	}
+
+	for _, item := range items {
+		description := item.Content
+		if description == "" {
+			description = item.Summary
+		}
+		doc.Channel.Items = append(doc.Channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			GUID:        rssGUID{Value: item.ID},
+			Description: description,
+			Categories:  item.Categories,
+			PubDate:     item.Published.Format(time.RFC1123Z),
+		})
+	}
+
+	return marshalFeed(doc)
+}
+
+// BuildAtom renders the items as an RFC 4287 Atom document
+func BuildAtom(channel FeedChannel, items []FeedItem) ([]byte, error) {
+	feed := atomFeed{
+		Lang:    channel.Language,
+		ID:      channel.SelfLink,
+		Title:   channel.Title,
+		Updated: channel.Updated.Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: channel.Link, Rel: "alternate", Type: "text/html"},
+			{Href: channel.SelfLink, Rel: "self", Type: "application/atom+xml"},
+		},
+		Entries: make([]atomEntry, 0, len(items)),
+	}
+
+	for _, item := range items {
+		entry := atomEntry{
+			ID:        item.ID,
+			Title:     item.Title,
+			Link:      atomLink{Href: item.Link, Rel: "alternate", Type: "text/html"},
+			Published: item.Published.Format(time.RFC3339),
+			Updated:   item.Updated.Format(time.RFC3339),
+			Summary:   item.Summary,
+			// Atom requires an author on every entry when the feed has none
+			Author: atomAuthor{Name: channel.Title},
+		}
+		if item.Content != "" {
+			entry.Content = &atomContent{Type: "html", Value: item.Content}
+		}
+		for _, category := range item.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: category})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return marshalFeed(feed)
+}
+
+// marshalFeed encodes the document with an XML declaration
+func marshalFeed(doc interface{}) ([]byte, error) {
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
@@ -0,0 +1,91 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ICalEvent represents a single VEVENT in an iCalendar feed
+type ICalEvent struct {
+	UID          string
+	Sequence     int64
+	Status       string
+	Summary      string
+	Description  string
+	Location     string
+	Start        time.Time
+	End          time.Time
+	LastModified time.Time
+}
+
+const icalTimeFormat = "20060102T150405Z"
+
+// BuildICalendar renders the events as an RFC 5545 calendar
+func BuildICalendar(name string, events []ICalEvent) string {
+	var b strings.Builder
+
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:-//BEM IT Del//Kalender Kegiatan//ID")
+	writeICalLine(&b, "CALSCALE:GREGORIAN")
+	writeICalLine(&b, "METHOD:PUBLISH")
+	writeICalLine(&b, "X-WR-CALNAME:"+escapeICalText(name))
+	writeICalLine(&b, "X-WR-TIMEZONE:Asia/Jakarta")
+
+	for _, event := range events {
+		writeICalLine(&b, "BEGIN:VEVENT")
+		writeICalLine(&b, "UID:"+event.UID)
+		writeICalLine(&b, "DTSTAMP:"+event.LastModified.UTC().Format(icalTimeFormat))
+		writeICalLine(&b, "LAST-MODIFIED:"+event.LastModified.UTC().Format(icalTimeFormat))
+		writeICalLine(&b, fmt.Sprintf("SEQUENCE:%d", event.Sequence))
+		writeICalLine(&b, "DTSTART:"+event.Start.UTC().Format(icalTimeFormat))
+		writeICalLine(&b, "DTEND:"+event.End.UTC().Format(icalTimeFormat))
+		if event.Status != "" {
+			writeICalLine(&b, "STATUS:"+event.Status)
+		}
+		writeICalLine(&b, "SUMMARY:"+escapeICalText(event.Summary))
+		if event.Description != "" {
+			writeICalLine(&b, "DESCRIPTION:"+escapeICalText(event.Description))
+		}
+		if event.Location != "" {
+			writeICalLine(&b, "LOCATION:"+escapeICalText(event.Location))
+		}
+		writeICalLine(&b, "END:VEVENT")
+	}
+
+	writeICalLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// escapeICalText escapes characters that have a special meaning in TEXT values
+func escapeICalText(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(s)
+}
+
+// writeICalLine writes a content line folded at 75 octets, as required by RFC 5545
+func writeICalLine(b *strings.Builder, line string) {
+	// Continuation lines start with a space, which counts towards the limit
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		// Do not split a multi-byte UTF-8 character
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,89 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// MaxSlugLength is the longest slug generated from a title, excluding the
+// collision suffix
+const MaxSlugLength = 80
+
+// slugSymbolReplacer spells out symbols that commonly appear in Indonesian
+// titles, so "BEM & HIMA" becomes "bem-dan-hima" instead of "bem-hima"
+var slugSymbolReplacer = strings.NewReplacer(
+	"&", " dan ",
+	"%", " persen ",
+	"@", " di ",
+	"+", " plus ",
+	"'", "",
+	"’", "",
+	"`", "",
+)
+
+// slugLetters maps accented and special Latin letters to plain ASCII
+var slugLetters = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'æ': "ae",
+	'ç': "c", 'è': "e", 'é': "e", 'ê': "e", 'ë': "e",
+	'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ñ': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'œ': "oe",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ý': "y", 'ÿ': "y", 'ß': "ss",
+}
+
+// Slugify turns a title into a lowercase, hyphen-separated ASCII slug.
+// It returns an empty string when the title has no usable characters.
+func Slugify(text string) string {
+	text = slugSymbolReplacer.Replace(strings.ToLower(text))
+
+	var b strings.Builder
+	hyphen := false
+	for _, r := range text {
+		if replacement, ok := slugLetters[r]; ok {
+			b.WriteString(replacement)
+			hyphen = false
+			continue
+		}
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		if !hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+			hyphen = true
+		}
+	}
+
+	slug := strings.Trim(b.String(), "-")
+	if len(slug) > MaxSlugLength {
+		slug = slug[:MaxSlugLength]
+		// Cut at a word boundary when there is one
+		if i := strings.LastIndexByte(slug, '-'); i > MaxSlugLength/2 {
+			slug = slug[:i]
+		}
+		slug = strings.Trim(slug, "-")
+	}
+	return slug
+}
+
+// UniqueSlug slugifies title and appends -2, -3, ... until taken reports the
+// slug as free. fallback is used when the title produces an empty slug.
+func UniqueSlug(title, fallback string, taken func(slug string) (bool, error)) (string, error) {
+	base := Slugify(title)
+	if base == "" {
+		base = fallback
+	}
+
+	slug := base
+	for i := 2; ; i++ {
+		exists, err := taken(slug)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+		slug = fmt.Sprintf("%s-%d", base, i)
+	}
+}
@@ -0,0 +1,195 @@
+package utils
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultBannedWords is the built-in list of Indonesian and English profanity
+// used when no banned-word file is configured.
+var DefaultBannedWords = []string{
+	// Indonesian
+	"anjing", "anjir", "bangsat", "bajingan", "brengsek", "keparat", "kampret",
+	"goblok", "tolol", "idiot", "babi", "monyet", "kontol", "memek", "ngentot",
+	"jancok", "jancuk", "asu", "lonte", "perek", "pelacur", "tai", "taik",
+	// English
+	"fuck", "fucking", "motherfucker", "shit", "bullshit", "bitch", "bastard",
+	"asshole", "dick", "cunt", "whore", "slut", "retard",
+}
+
+// leetReplacer maps common character substitutions back to letters so that
+// "g0bl0k" or "sh1t" are still caught by the filter.
+var leetReplacer = strings.NewReplacer(
+	"0", "o", "1", "i", "3", "e", "4", "a", "5", "s", "7", "t", "@", "a", "$", "s",
+)
+
+// WordFilter finds banned words and phrases in free text.
+type WordFilter struct {
+	// words maps a squeezed single-word entry to the entries as configured
+	words map[string][]string
+	// phrases holds multi-word entries as space-joined normalized tokens
+	phrases []string
+}
+
+// NewWordFilter creates a filter for the given words. Entries containing spaces
+// are matched as phrases.
+func NewWordFilter(words []string) *WordFilter {
+	filter := &WordFilter{words: make(map[string][]string)}
+	for _, word := range words {
+		tokens := normalizeTokens(word)
+		switch len(tokens) {
+		case 0:
+			continue
+		case 1:
+			key := squeeze(tokens[0])
+			filter.words[key] = append(filter.words[key], tokens[0])
+		default:
+			filter.phrases = append(filter.phrases, strings.Join(tokens, " "))
+		}
+	}
+	return filter
+}
+
+// NewWordFilterFromEnv builds a filter from BANNED_WORDS_FILE (one entry per
+// line, replacing the built-in list) and BANNED_WORDS (comma-separated entries
+// added on top of it).
+func NewWordFilterFromEnv() *WordFilter {
+	words := DefaultBannedWords
+	if path := os.Getenv("BANNED_WORDS_FILE"); path != "" {
+		fileWords, err := readWordFile(path)
+		if err != nil {
+			log.Printf("Failed to read banned words file %s, using built-in list: %v", path, err)
+		} else {
+			words = fileWords
+		}
+	}
+	if extra := os.Getenv("BANNED_WORDS"); extra != "" {
+		words = append(append([]string{}, words...), strings.Split(extra, ",")...)
+	}
+	return NewWordFilter(words)
+}
+
+// readWordFile reads one banned entry per line, skipping blanks and # comments.
+func readWordFile(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	return words, scanner.Err()
+}
+
+// normalizeTokens lowercases text and splits it into words on anything that is
+// not a letter, a digit or a leetspeak symbol. Leetspeak is only undone inside
+// words that also contain letters, so numbers such as "2024" are never read as
+// words, and words that still contain other characters afterwards are dropped.
+func normalizeTokens(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '@' && r != '$'
+	})
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		if strings.IndexFunc(word, unicode.IsLetter) < 0 {
+			continue
+		}
+		word = leetReplacer.Replace(word)
+		if strings.IndexFunc(word, func(r rune) bool { return !unicode.IsLetter(r) }) >= 0 {
+			continue
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// letterRun is a run of the same letter within a word.
+type letterRun struct {
+	letter rune
+	length int
+}
+
+// runs splits a word into runs of the same letter.
+func runs(token string) []letterRun {
+	var result []letterRun
+	for _, r := range token {
+		if n := len(result); n > 0 && result[n-1].letter == r {
+			result[n-1].length++
+			continue
+		}
+		result = append(result, letterRun{letter: r, length: 1})
+	}
+	return result
+}
+
+// squeeze collapses runs of the same letter into a single letter. It is used as
+// the lookup key for banned words.
+func squeeze(token string) string {
+	var b strings.Builder
+	for _, run := range runs(token) {
+		b.WriteRune(run.letter)
+	}
+	return b.String()
+}
+
+// stretchedFrom reports whether token spells word, allowing a letter to be
+// stretched to three or more repetitions ("anjiiing" for "anjing"). Doubled
+// letters are compared exactly, so ordinary words are not squeezed into a
+// banned word.
+func stretchedFrom(token, word string) bool {
+	tokenRuns, wordRuns := runs(token), runs(word)
+	if len(tokenRuns) != len(wordRuns) {
+		return false
+	}
+	for i, run := range tokenRuns {
+		if run.letter != wordRuns[i].letter {
+			return false
+		}
+		if run.length != wordRuns[i].length && run.length < 3 {
+			return false
+		}
+	}
+	return true
+}
+
+// Match returns the banned entries found in text, sorted and without duplicates.
+// Single words must match a whole word of the text; phrases must match whole words
+// in sequence.
+func (f *WordFilter) Match(text string) []string {
+	tokens := normalizeTokens(text)
+	found := make(map[string]bool)
+	for _, token := range tokens {
+		for _, word := range f.words[squeeze(token)] {
+			if stretchedFrom(token, word) {
+				found[word] = true
+			}
+		}
+	}
+	if len(f.phrases) > 0 {
+		joined := " " + strings.Join(tokens, " ") + " "
+		for _, phrase := range f.phrases {
+			if strings.Contains(joined, " "+phrase+" ") {
+				found[phrase] = true
+			}
+		}
+	}
+
+	matches := make([]string, 0, len(found))
+	for word := range found {
+		matches = append(matches, word)
+	}
+	sort.Strings(matches)
+	return matches
+}